@@ -0,0 +1,135 @@
+package gorkov
+
+import (
+	"io"
+)
+
+// SliceTokenizer is a Tokenizer that returns the tokens of a slice.
+type SliceTokenizer struct {
+	tokens []Token
+}
+
+// NewSliceTokenizer creates a new SliceTokenizer returning the given tokens
+// in order.
+func NewSliceTokenizer(tokens ...Token) *SliceTokenizer {
+	return &SliceTokenizer{tokens: tokens}
+}
+
+// Next returns the next token of the slice or io.EOF if there are no more
+// tokens.
+func (s *SliceTokenizer) Next() (Token, error) {
+	if len(s.tokens) == 0 {
+		return nil, io.EOF
+	}
+	t := s.tokens[0]
+	s.tokens = s.tokens[1:]
+	return t, nil
+}
+
+// Collect reads tokens from t until it returns an error. If that error is
+// io.EOF, all tokens and a nil error are returned. Otherwise the tokens read
+// so far are returned together with the error.
+func Collect(t Tokenizer) ([]Token, error) {
+	var tokens []Token
+	for {
+		token, err := t.Next()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// Map wraps a Tokenizer. The new Tokenizer returns f(token) for every token
+// returned by t. Errors returned by t are returned unmodified.
+func Map(t Tokenizer, f func(Token) Token) Tokenizer {
+	return TokenizerFunc(func() (Token, error) {
+		token, err := t.Next()
+		if err != nil {
+			return nil, err
+		}
+		return f(token), nil
+	})
+}
+
+// Filter wraps a Tokenizer. The new Tokenizer only returns the tokens of t
+// for which keep returns true. Errors returned by t are returned unmodified.
+func Filter(t Tokenizer, keep func(Token) bool) Tokenizer {
+	return TokenizerFunc(func() (Token, error) {
+		for {
+			token, err := t.Next()
+			if err != nil {
+				return nil, err
+			}
+			if keep(token) {
+				return token, nil
+			}
+		}
+	})
+}
+
+// Concat returns a Tokenizer that returns the tokens of all given tokenizers
+// in order, reading from the next one once the current one returned io.EOF.
+// An End token is returned between the tokens of two tokenizers, unless no
+// tokens have been returned yet or the last token was already an End token.
+// Errors other than io.EOF are returned unmodified and the failing tokenizer
+// is not skipped.
+func Concat(ts ...Tokenizer) Tokenizer {
+	atEnd := true
+	return TokenizerFunc(func() (Token, error) {
+		for len(ts) > 0 {
+			token, err := ts[0].Next()
+			if err == io.EOF {
+				ts = ts[1:]
+				if len(ts) > 0 && !atEnd {
+					atEnd = true
+					return End, nil
+				}
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			atEnd = TokensEqual(token, End)
+			return token, nil
+		}
+		return nil, io.EOF
+	})
+}
+
+// Limit wraps a Tokenizer. The new Tokenizer returns at most the first n
+// tokens of t and io.EOF afterwards. Errors returned by t are returned
+// unmodified.
+func Limit(t Tokenizer, n int) Tokenizer {
+	return TokenizerFunc(func() (Token, error) {
+		if n <= 0 {
+			return nil, io.EOF
+		}
+		token, err := t.Next()
+		if err != nil {
+			return nil, err
+		}
+		n--
+		return token, nil
+	})
+}
+
+// Tee wraps a Tokenizer. The new Tokenizer returns the same stream of tokens
+// as t and also passes every token to sink before returning it. If sink
+// returns an error, that error is returned instead of the token. Errors
+// returned by t are returned unmodified and not passed to sink.
+func Tee(t Tokenizer, sink func(Token) error) Tokenizer {
+	return TokenizerFunc(func() (Token, error) {
+		token, err := t.Next()
+		if err != nil {
+			return nil, err
+		}
+		if err := sink(token); err != nil {
+			return nil, err
+		}
+		return token, nil
+	})
+}
@@ -0,0 +1,128 @@
+package gorkov_test
+
+import (
+	"errors"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("Combinators", func() {
+	testErr := errors.New("test error")
+
+	// failing returns a Tokenizer that returns the given tokens followed by
+	// testErr.
+	failing := func(tokens ...Token) Tokenizer {
+		s := NewSliceTokenizer(tokens...)
+		return TokenizerFunc(func() (Token, error) {
+			t, err := s.Next()
+			if err == io.EOF {
+				return nil, testErr
+			}
+			return t, err
+		})
+	}
+
+	Describe("SliceTokenizer and Collect", func() {
+		It("should return the tokens of the slice", func() {
+			tokens := makeTokens("foo", " ", End)
+			Expect(Collect(NewSliceTokenizer(tokens...))).To(Equal(tokens))
+		})
+		It("should return io.EOF for an empty slice", func() {
+			_, err := NewSliceTokenizer().Next()
+			Expect(err).To(Equal(io.EOF))
+		})
+		It("should return the tokens read before an error", func() {
+			tokens, err := Collect(failing(makeTokens("foo", "bar")...))
+			Expect(tokens).To(Equal(makeTokens("foo", "bar")))
+			Expect(err).To(Equal(testErr))
+		})
+	})
+
+	Describe("Map", func() {
+		It("should transform every token", func() {
+			tokens, err := Collect(Map(NewSliceTokenizer(makeTokens("foo", End)...), func(t Token) Token {
+				return Literal(t.Value() + "!")
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal(makeTokens("foo!", "!")))
+		})
+		It("should return errors", func() {
+			_, err := Collect(Map(failing(), func(t Token) Token { return t }))
+			Expect(err).To(Equal(testErr))
+		})
+	})
+
+	Describe("Filter", func() {
+		It("should drop tokens", func() {
+			tokens, err := Collect(Filter(NewSliceTokenizer(makeTokens("a", " ", "b", End)...), func(t Token) bool {
+				return t.Value() != " "
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal(makeTokens("a", "b", End)))
+		})
+		It("should return errors", func() {
+			_, err := Collect(Filter(failing(Literal("a")), func(t Token) bool { return false }))
+			Expect(err).To(Equal(testErr))
+		})
+	})
+
+	Describe("Concat", func() {
+		It("should return all tokens with End in between", func() {
+			tokens, err := Collect(Concat(
+				NewSliceTokenizer(),
+				NewSliceTokenizer(makeTokens("a", "b")...),
+				NewSliceTokenizer(makeTokens("c", End)...),
+				NewSliceTokenizer(),
+				NewSliceTokenizer(makeTokens("d")...),
+			))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal(makeTokens("a", "b", End, "c", End, "d")))
+		})
+		It("should return io.EOF without tokenizers", func() {
+			_, err := Concat().Next()
+			Expect(err).To(Equal(io.EOF))
+		})
+		It("should return errors", func() {
+			tokens, err := Collect(Concat(NewSliceTokenizer(Literal("a")), failing(Literal("b"))))
+			Expect(tokens).To(Equal(makeTokens("a", End, "b")))
+			Expect(err).To(Equal(testErr))
+		})
+	})
+
+	Describe("Limit", func() {
+		It("should return at most n tokens", func() {
+			Expect(Collect(Limit(NewSliceTokenizer(makeTokens("a", "b", "c")...), 2))).To(Equal(makeTokens("a", "b")))
+			Expect(Collect(Limit(NewSliceTokenizer(makeTokens("a")...), 2))).To(Equal(makeTokens("a")))
+			Expect(Collect(Limit(failing(), 0))).To(BeEmpty())
+		})
+		It("should return errors", func() {
+			_, err := Collect(Limit(failing(Literal("a")), 2))
+			Expect(err).To(Equal(testErr))
+		})
+	})
+
+	Describe("Tee", func() {
+		It("should pass every token to the sink", func() {
+			var seen []Token
+			tokens, err := Collect(Tee(NewSliceTokenizer(makeTokens("a", End)...), func(t Token) error {
+				seen = append(seen, t)
+				return nil
+			}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tokens).To(Equal(makeTokens("a", End)))
+			Expect(seen).To(Equal(tokens))
+		})
+		It("should return errors of the sink", func() {
+			_, err := Tee(NewSliceTokenizer(Literal("a")), func(Token) error { return testErr }).Next()
+			Expect(err).To(Equal(testErr))
+		})
+		It("should return errors of the tokenizer", func() {
+			_, err := Collect(Tee(failing(), func(Token) error { return nil }))
+			Expect(err).To(Equal(testErr))
+		})
+	})
+})
@@ -0,0 +1,21 @@
+package gorkov
+
+import (
+	"context"
+)
+
+// WithContext wraps a Tokenizer. The new Tokenizer returns the same stream of
+// tokens as t until ctx is done. After that it returns ctx.Err() without
+// reading from t.
+//
+// ctx is only checked between tokens, before every call to t.Next. A Next
+// call that blocks, for example on a slow network connection, is not
+// interrupted.
+func WithContext(ctx context.Context, t Tokenizer) Tokenizer {
+	return TokenizerFunc(func() (Token, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return t.Next()
+	})
+}
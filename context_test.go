@@ -0,0 +1,44 @@
+package gorkov_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("WithContext", func() {
+	It("should return all tokens if the context is not done", func() {
+		tokens, err := Collect(WithContext(context.Background(), NewTokenizer(strings.NewReader("foo bar\n"))))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(Equal(makeTokens("foo", " ", "bar", End)))
+	})
+
+	It("should return the context's error after cancellation", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		tokenizer := WithContext(ctx, NewTokenizer(strings.NewReader("foo bar\n")))
+		token, err := tokenizer.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal(Literal("foo")))
+		cancel()
+		_, err = tokenizer.Next()
+		Expect(err).To(Equal(context.Canceled))
+		_, err = tokenizer.Next()
+		Expect(err).To(Equal(context.Canceled))
+	})
+
+	It("should not read from the tokenizer after the deadline", func() {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		_, err := WithContext(ctx, TokenizerFunc(func() (Token, error) {
+			Fail("the tokenizer should not be read from")
+			return nil, io.EOF
+		})).Next()
+		Expect(err).To(Equal(context.DeadlineExceeded))
+	})
+})
@@ -0,0 +1,337 @@
+package gorkov
+
+import (
+	"net"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// URLType is the type used for tokens containing a URL.
+	URLType = "u"
+
+	// EmailType is the type used for tokens containing an e-mail address.
+	EmailType = "m"
+
+	// MentionType is the type used for tokens containing a mention of a
+	// user, such as "@gopher".
+	MentionType = "a"
+
+	// HashtagType is the type used for tokens containing a hashtag, such as
+	// "#golang".
+	HashtagType = "h"
+)
+
+// emailPattern matches candidates for e-mail addresses.
+const emailPattern = `[\p{L}\p{N}._%+-]+@(?:[a-z0-9-]+\.)+[a-z]{2,}`
+
+// entityPattern matches candidates for entities. The submatches are, in
+// order, URLs, e-mail addresses, mentions and hashtags. Candidates still have
+// to be validated, see matchEntity.
+var entityPattern = regexp.MustCompile(`(?i)` +
+	`((?:https?://|www\.)[^\s\p{Z}]+)|` +
+	`(` + emailPattern + `)|` +
+	`(@[\p{L}\p{N}_]+)|` +
+	`(#[\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*)`)
+
+// RecognizeEntities wraps a Tokenizer. The new Tokenizer returns the same
+// stream of tokens as the original, except that URLs, e-mail addresses,
+// mentions and hashtags are returned as single tokens of type URLType,
+// EmailType, MentionType and HashtagType respectively. Both the identifier
+// and the value of these tokens are the text of the entity.
+//
+// Only literal tokens are inspected. Entities never span whitespace or
+// tokens of other types, such as End. Literal tokens that are only partially
+// part of an entity are split, all other tokens are returned unmodified.
+//
+// URLs have to start with "http://", "https://" or "www.". Trailing
+// punctuation, such as a full stop or an unbalanced closing parenthesis, is
+// not considered part of a URL. The domains of URLs and e-mail addresses have
+// to be IP addresses or end in a public suffix known to the
+// golang.org/x/net/publicsuffix package. Only ASCII domains are recognized.
+func RecognizeEntities(t Tokenizer) Tokenizer {
+	return &entityTokenizer{t: t, match: matchEntity}
+}
+
+// entityBufferSize is the number of bytes of text without whitespace that an
+// entityTokenizer keeps buffered at least before flushing part of it.
+// Entities that are longer than that might not be recognized.
+const entityBufferSize = 4096
+
+// entityMatcher returns the first entity in s that starts at or after the
+// byte offset i, together with the token that replaces it. If there is no
+// such entity, the returned token is nil.
+type entityMatcher func(s string, i int) (start, end int, t Token)
+
+// entityTokenizer buffers the values of contiguous literal tokens until
+// whitespace is encountered and replaces the entities found by match in
+// them. If spanSpaces is true, entities may contain whitespace, but they may
+// not be longer than maxSpan bytes. The buffer is then only flushed up to
+// text that cannot be part of an entity containing the whitespace, or
+// completely when a token that is not a literal or an error is encountered.
+// Long runs of text without whitespace are flushed once they exceed twice
+// entityBufferSize. If found is not nil, it is called for every entity that is queued.
+type entityTokenizer struct {
+	t          Tokenizer
+	match      entityMatcher
+	spanSpaces bool
+	maxSpan    int
+	found      func(Token)
+	err        error
+
+	// buf contains the buffered text. cuts contains the offsets in buf at
+	// which the buffered tokens end.
+	buf  []byte
+	cuts []int
+
+	queue []Token
+}
+
+func (e *entityTokenizer) Next() (Token, error) {
+	for len(e.queue) == 0 {
+		if e.err != nil {
+			return nil, e.err
+		}
+		e.fill()
+	}
+	t := e.queue[0]
+	e.queue = e.queue[1:]
+	return t, nil
+}
+
+// fill reads the next token from the underlying Tokenizer and flushes as much
+// of the buffer as possible.
+func (e *entityTokenizer) fill() {
+	token, err := e.t.Next()
+	switch {
+	case err != nil:
+		e.flush(len(e.buf), len(e.buf))
+		e.err = err
+	case token.Type() != LiteralType:
+		e.flush(len(e.buf), len(e.buf))
+		e.queue = append(e.queue, token)
+	default:
+		start := len(e.buf)
+		e.buf = append(e.buf, token.Value()...)
+		e.cuts = append(e.cuts, len(e.buf))
+		i := strings.IndexFunc(token.Value(), unicode.IsSpace)
+		switch {
+		case i >= 0 && e.spanSpaces:
+			e.flushBefore(start + i - e.maxSpan)
+		case i >= 0:
+			e.flush(start, start+i)
+		}
+		if len(e.buf) >= 2*entityBufferSize {
+			e.flushBefore(len(e.buf) - entityBufferSize)
+		}
+	}
+}
+
+// flushBefore flushes the buffer up to the last offset at or before n at
+// which a buffered token ends, provided that the offset is a boundary as
+// defined by isBoundary.
+func (e *entityTokenizer) flushBefore(n int) {
+	limit := 0
+	for _, c := range e.cuts {
+		if c > n {
+			break
+		}
+		if e.isBoundary(c) {
+			limit = c
+		}
+	}
+	if limit > 0 {
+		e.flush(limit, len(e.buf))
+	}
+}
+
+// isBoundary returns true if matches starting at the offset c in buf do not
+// depend on the text before c. That is the case unless c follows a letter, a
+// digit, an underscore or a number separator following a digit. Offsets
+// between two letters are boundaries as well, since every match that contains
+// the letter after the offset also contains the one before it.
+func (e *entityTokenizer) isBoundary(c int) bool {
+	prev, width := utf8.DecodeLastRune(e.buf[:c])
+	next, _ := utf8.DecodeRune(e.buf[c:])
+	switch {
+	case unicode.IsLetter(prev) && unicode.IsLetter(next):
+		return true
+	case isAlnum(prev) || prev == '_':
+		return false
+	case isNumberSeparator(prev):
+		before, _ := utf8.DecodeLastRune(e.buf[:c-width])
+		return !unicode.IsDigit(before)
+	default:
+		return true
+	}
+}
+
+// flush queues all entities in buf[:end] that start before limit and all
+// text before limit or the end of the last such entity, whichever is later.
+// Flushed text is removed from the buffer.
+func (e *entityTokenizer) flush(limit, end int) {
+	s := string(e.buf[:end])
+	pos := 0
+	for pos < limit {
+		start, stop, t := e.match(s, pos)
+		if t == nil || start >= limit {
+			break
+		}
+		e.literals(s, pos, start)
+		e.queue = append(e.queue, t)
+		if e.found != nil {
+			e.found(t)
+		}
+		pos = stop
+	}
+	if pos < limit {
+		e.literals(s, pos, limit)
+		pos = limit
+	}
+	e.buf = append(e.buf[:0], e.buf[pos:]...)
+	cuts := e.cuts[:0]
+	for _, c := range e.cuts {
+		if c > pos {
+			cuts = append(cuts, c-pos)
+		}
+	}
+	e.cuts = cuts
+}
+
+// literals queues the text in s[from:to], where s is a prefix of buf, as
+// literal tokens, split at the same offsets as the original tokens.
+func (e *entityTokenizer) literals(s string, from, to int) {
+	for _, c := range e.cuts {
+		if c <= from {
+			continue
+		}
+		if c >= to {
+			break
+		}
+		e.queue = append(e.queue, Literal(s[from:c]))
+		from = c
+	}
+	if from < to {
+		e.queue = append(e.queue, Literal(s[from:to]))
+	}
+}
+
+// matchEntity is an entityMatcher for URLs, e-mail addresses, mentions and
+// hashtags.
+func matchEntity(s string, i int) (int, int, Token) {
+	for i < len(s) {
+		m := entityPattern.FindStringSubmatchIndex(s[i:])
+		if m == nil {
+			break
+		}
+		start := i + m[0]
+		if end, t := validateEntity(s, start, i+m[1], m); t != nil {
+			return start, end, t
+		}
+		if m[2] >= 0 {
+			// parts of a rejected URL are not entities of their own
+			i += m[1]
+			continue
+		}
+		_, width := utf8.DecodeRuneInString(s[start:])
+		i = start + width
+	}
+	return 0, 0, nil
+}
+
+// validateEntity checks the candidate s[start:end] matched by entityPattern
+// with the submatch indices m. If it is a valid entity, the end of the entity
+// and its token are returned. Otherwise the returned token is nil.
+func validateEntity(s string, start, end int, m []int) (int, Token) {
+	switch {
+	case m[2] >= 0:
+		url := trimURL(s[start:end])
+		if validHost(urlHost(url)) {
+			return start + len(url), NewToken(URLType, url)
+		}
+	case m[4] >= 0 && validEmail(s[start:end]):
+		return end, NewToken(EmailType, s[start:end])
+	case m[6] >= 0 && !followsWord(s, start):
+		return end, NewToken(MentionType, s[start:end])
+	case m[8] >= 0 && !followsWord(s, start):
+		return end, NewToken(HashtagType, s[start:end])
+	}
+	return 0, nil
+}
+
+// followsWord returns true if the rune before the byte offset i in s is a
+// letter, a digit or an underscore.
+func followsWord(s string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return i > 0 && (isAlnum(r) || r == '_')
+}
+
+// trimURL removes trailing punctuation from url. Closing parentheses are
+// only removed if they have no matching opening parenthesis in url.
+func trimURL(url string) string {
+	for len(url) > 0 {
+		r, width := utf8.DecodeLastRuneInString(url)
+		switch {
+		case r == ')' && strings.Count(url, "(") >= strings.Count(url, ")"):
+			return url
+		case strings.ContainsRune(".,;:!?'\"’”)]}>", r):
+			url = url[:len(url)-width]
+		default:
+			return url
+		}
+	}
+	return url
+}
+
+// urlHost returns the host part of url, without user information or port.
+func urlHost(url string) string {
+	_, host := urlAuthority(url)
+	return host
+}
+
+// urlAuthority returns the user information and the host of url. The port is
+// removed from the host.
+func urlAuthority(url string) (userinfo, host string) {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	}
+	if i := strings.IndexAny(url, "/?#"); i >= 0 {
+		url = url[:i]
+	}
+	if i := strings.LastIndex(url, "@"); i >= 0 {
+		userinfo, url = url[:i], url[i+1:]
+	}
+	if host, _, err := net.SplitHostPort(url); err == nil {
+		return userinfo, host
+	}
+	return userinfo, url
+}
+
+// validEmail returns true if the domain of the e-mail address email is valid
+// as defined by validDomain.
+func validEmail(email string) bool {
+	return validDomain(email[strings.LastIndex(email, "@")+1:])
+}
+
+// validHost returns true if host is an IP address or a valid domain as
+// defined by validDomain.
+func validHost(host string) bool {
+	return net.ParseIP(host) != nil || validDomain(host)
+}
+
+// validDomain returns true if domain ends in a public suffix and contains at
+// least one label in front of it.
+func validDomain(domain string) bool {
+	domain = strings.ToLower(domain)
+	if _, err := publicsuffix.EffectiveTLDPlusOne(domain); err != nil {
+		return false
+	}
+	// Unknown top level domains are reported as non-ICANN suffixes consisting
+	// of a single label, known private suffixes always contain a dot.
+	suffix, icann := publicsuffix.PublicSuffix(domain)
+	return icann || strings.Contains(suffix, ".")
+}
@@ -0,0 +1,134 @@
+package gorkov_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("RecognizeEntities", func() {
+	DescribeTable("reading from a ReaderTokenizer",
+		func(input string, expected []Token) {
+			Expect(readAll(RecognizeEntities(NewTokenizer(strings.NewReader(input))))).To(Equal(expected))
+		},
+		Entry(
+			"empty input",
+			"",
+			[]Token{},
+		),
+		Entry(
+			"no entities",
+			"foo bar, baz\n",
+			makeTokens("foo", " ", "bar", ", ", "baz", End),
+		),
+		Entry(
+			"a URL",
+			"see https://golang.org/doc/faq?x=1#top now\n",
+			makeTokens("see", " ", NewToken(URLType, "https://golang.org/doc/faq?x=1#top"), " ", "now", End),
+		),
+		Entry(
+			"a URL with trailing punctuation",
+			"(see www.example.co.uk/a_(b)).",
+			makeTokens("(", "see", " ", NewToken(URLType, "www.example.co.uk/a_(b)"), ")."),
+		),
+		Entry(
+			"a URL with an IP address and port",
+			"http://127.0.0.1:8080/",
+			makeTokens(NewToken(URLType, "http://127.0.0.1:8080/")),
+		),
+		Entry(
+			"a URL with an unknown top level domain",
+			"http://foo.invalidtld",
+			makeTokens("http", "://", "foo", ".", "invalidtld"),
+		),
+		Entry(
+			"entities inside a URL with an unknown top level domain",
+			"https://foo.invalidtld/a@golang.org #x",
+			makeTokens("https", "://", "foo", ".", "invalidtld", "/", "a", "@", "golang", ".", "org", " ",
+				NewToken(HashtagType, "#x")),
+		),
+		Entry(
+			"an e-mail address",
+			"mail gopher@golang.org, please",
+			makeTokens("mail", " ", NewToken(EmailType, "gopher@golang.org"), ", ", "please"),
+		),
+		Entry(
+			"an e-mail address with an unknown top level domain",
+			"gopher@golang.invalidtld",
+			makeTokens("gopher", "@", "golang", ".", "invalidtld"),
+		),
+		Entry(
+			"mentions and hashtags",
+			"\"@gopher: #golang!\"",
+			makeTokens("\"", NewToken(MentionType, "@gopher"), ": ", NewToken(HashtagType, "#golang"), "!\""),
+		),
+		Entry(
+			"mentions and hashtags following punctuation and whitespace",
+			"hi, @gopher #go",
+			makeTokens("hi", ", ", NewToken(MentionType, "@gopher"), " ", NewToken(HashtagType, "#go")),
+		),
+		Entry(
+			"hashtags without letters",
+			"#1 fan",
+			makeTokens("#", "1", " ", "fan"),
+		),
+		Entry(
+			"mentions following a word",
+			"foo@bar",
+			makeTokens("foo", "@", "bar"),
+		),
+		Entry(
+			"entities on multiple lines",
+			"#foo\n@bar\n",
+			makeTokens(NewToken(HashtagType, "#foo"), End, NewToken(MentionType, "@bar"), End),
+		),
+	)
+	Describe("reading from a Tokenizer that returns an error", func() {
+		It("should return the buffered tokens and the error", func() {
+			expectedErr := errors.New("test error")
+			tokens := makeTokens("@", "gopher")
+			tokenizer := RecognizeEntities(TokenizerFunc(func() (Token, error) {
+				if len(tokens) == 0 {
+					return nil, expectedErr
+				}
+				t := tokens[0]
+				tokens = tokens[1:]
+				return t, nil
+			}))
+			token, err := tokenizer.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal(NewToken(MentionType, "@gopher")))
+			_, err = tokenizer.Next()
+			Expect(err).To(Equal(expectedErr))
+			_, err = tokenizer.Next()
+			Expect(err).To(Equal(expectedErr))
+		})
+	})
+	Describe("reading a long line without whitespace", func() {
+		It("should recognize all entities", func() {
+			input := strings.Repeat("a,", 5000) + "gopher@golang.org," + strings.Repeat("a,", 5000) + "#golang"
+			var entities []Token
+			for _, t := range readAll(RecognizeEntities(NewTokenizer(strings.NewReader(input)))) {
+				if t.Type() != LiteralType {
+					entities = append(entities, t)
+				}
+			}
+			Expect(entities).To(Equal(makeTokens(NewToken(EmailType, "gopher@golang.org"), NewToken(HashtagType, "#golang"))))
+		})
+	})
+	Describe("reading from a Tokenizer that returns io.EOF", func() {
+		It("should keep returning io.EOF", func() {
+			tokenizer := RecognizeEntities(NewTokenizer(strings.NewReader("")))
+			_, err := tokenizer.Next()
+			Expect(err).To(Equal(io.EOF))
+			_, err = tokenizer.Next()
+			Expect(err).To(Equal(io.EOF))
+		})
+	})
+})
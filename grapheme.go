@@ -0,0 +1,264 @@
+package gorkov
+
+import (
+	"bufio"
+	"io"
+	"unicode"
+)
+
+// NewCharacterTokenizer creates a new ReaderTokenizer for the given reader
+// that returns one literal token per extended grapheme cluster, that is per
+// user-perceived character. Base characters with combining marks, emoji
+// sequences joined by ZERO WIDTH JOINER, emoji with modifiers and flags are
+// each returned as a single token. Like NewTokenizer it turns newlines into
+// End tokens.
+//
+// Grapheme clusters are determined following the rules of Unicode Standard
+// Annex #29, except that prepended concatenation marks are not supported and
+// emoji are recognized using a slightly simplified set of code points.
+func NewCharacterTokenizer(r io.Reader) *ReaderTokenizer {
+	s := bufio.NewScanner(r)
+	s.Split(scanGraphemes)
+	return &ReaderTokenizer{s: s}
+}
+
+// graphemeClass is the Grapheme_Cluster_Break property of a rune, as defined
+// by Unicode Standard Annex #29.
+type graphemeClass int
+
+const (
+	gcOther graphemeClass = iota
+	gcCR
+	gcLF
+	gcControl
+	gcExtend
+	gcZWJ
+	gcRegionalIndicator
+	gcSpacingMark
+	gcL
+	gcV
+	gcT
+	gcLV
+	gcLVT
+	gcPictographic
+)
+
+// hangul contains the ranges of the Hangul jamo used in grapheme clusters.
+var (
+	hangulL = &unicode.RangeTable{R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1},
+		{Lo: 0xa960, Hi: 0xa97c, Stride: 1},
+	}}
+	hangulV = &unicode.RangeTable{R16: []unicode.Range16{
+		{Lo: 0x1160, Hi: 0x11a7, Stride: 1},
+		{Lo: 0xd7b0, Hi: 0xd7c6, Stride: 1},
+	}}
+	hangulT = &unicode.RangeTable{R16: []unicode.Range16{
+		{Lo: 0x11a8, Hi: 0x11ff, Stride: 1},
+		{Lo: 0xd7cb, Hi: 0xd7fb, Stride: 1},
+	}}
+)
+
+// pictographic approximates the Extended_Pictographic property.
+var pictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00a9, Hi: 0x00ae, Stride: 5},
+		{Lo: 0x203c, Hi: 0x2049, Stride: 13},
+		{Lo: 0x2122, Hi: 0x2139, Stride: 23},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21a9, Hi: 0x21aa, Stride: 1},
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x2328, Hi: 0x23cf, Stride: 167},
+		{Lo: 0x23e9, Hi: 0x23f3, Stride: 1},
+		{Lo: 0x23f8, Hi: 0x23fa, Stride: 1},
+		{Lo: 0x24c2, Hi: 0x24c2, Stride: 1},
+		{Lo: 0x25aa, Hi: 0x25ab, Stride: 1},
+		{Lo: 0x25b6, Hi: 0x25c0, Stride: 10},
+		{Lo: 0x25fb, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2600, Hi: 0x27bf, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2b05, Hi: 0x2b07, Stride: 1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 5},
+		{Lo: 0x3030, Hi: 0x303d, Stride: 13},
+		{Lo: 0x3297, Hi: 0x3299, Stride: 2},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f000, Hi: 0x1f1e5, Stride: 1},
+		{Lo: 0x1f200, Hi: 0x1f3fa, Stride: 1},
+		{Lo: 0x1f400, Hi: 0x1faff, Stride: 1},
+	},
+}
+
+// getGraphemeClass returns the Grapheme_Cluster_Break property of r.
+func getGraphemeClass(r rune) graphemeClass {
+	switch {
+	case r == '\r':
+		return gcCR
+	case r == '\n':
+		return gcLF
+	case r == 0x200d:
+		return gcZWJ
+	case isGraphemeExtend(r):
+		return gcExtend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gcControl
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		return gcRegionalIndicator
+	case unicode.Is(unicode.Mc, r):
+		return gcSpacingMark
+	case unicode.Is(pictographic, r):
+		return gcPictographic
+	default:
+		return getHangulClass(r)
+	}
+}
+
+// isGraphemeExtend returns true if r has the Grapheme_Cluster_Break property
+// Extend. Emoji modifiers are included, as recommended by Unicode Standard
+// Annex #29.
+func isGraphemeExtend(r rune) bool {
+	return r == 0x200c || r >= 0xe0020 && r <= 0xe007f || r >= 0x1f3fb && r <= 0x1f3ff ||
+		unicode.In(r, unicode.Mn, unicode.Me)
+}
+
+// getHangulClass returns the Grapheme_Cluster_Break property of r if r is a
+// Hangul syllable or jamo and gcOther otherwise.
+func getHangulClass(r rune) graphemeClass {
+	switch {
+	case r >= 0xac00 && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return gcLV
+		}
+		return gcLVT
+	case unicode.Is(hangulL, r):
+		return gcL
+	case unicode.Is(hangulV, r):
+		return gcV
+	case unicode.Is(hangulT, r):
+		return gcT
+	default:
+		return gcOther
+	}
+}
+
+// graphemeState keeps track of the runes seen in the current grapheme
+// cluster that are relevant for deciding whether to break.
+type graphemeState struct {
+	prev graphemeClass
+	// pictographic is true if the cluster so far ends in an
+	// Extended_Pictographic rune followed by any number of Extend runes and
+	// an optional ZWJ.
+	pictographic bool
+	// regional is the number of regional indicators at the end of the
+	// cluster.
+	regional int
+}
+
+// next returns true if there is a grapheme cluster boundary between the runes
+// seen so far and a rune of class c. It updates the state to include the rune.
+func (s *graphemeState) next(c graphemeClass) bool {
+	join := s.joins(c)
+	s.update(c)
+	return !join
+}
+
+// joins returns true if a rune of class c continues the current grapheme
+// cluster.
+func (s *graphemeState) joins(c graphemeClass) bool {
+	p := s.prev
+	if join, ok := controlRules(p, c); ok {
+		return join
+	}
+	switch {
+	case hangulRules(p, c):
+		return true
+	case c == gcExtend || c == gcZWJ || c == gcSpacingMark:
+		return true
+	case p == gcZWJ && c == gcPictographic:
+		return s.pictographic
+	case p == gcRegionalIndicator && c == gcRegionalIndicator:
+		return s.regional%2 == 1
+	}
+	return false
+}
+
+// update updates the state to include a rune of class c.
+func (s *graphemeState) update(c graphemeClass) {
+	switch {
+	case c == gcPictographic:
+		s.pictographic = true
+	case c == gcExtend && s.pictographic && s.prev != gcZWJ:
+	case c == gcZWJ && s.pictographic && s.prev != gcZWJ:
+	default:
+		s.pictographic = false
+	}
+	if c == gcRegionalIndicator {
+		s.regional++
+	} else {
+		s.regional = 0
+	}
+	s.prev = c
+}
+
+// controlRules applies the rules GB3 to GB5 to a rune of class p followed by
+// a rune of class c. ok is false if none of these rules applies.
+func controlRules(p, c graphemeClass) (join, ok bool) {
+	switch {
+	case p == gcCR && c == gcLF:
+		return true, true
+	case isControlClass(p) || isControlClass(c):
+		return false, true
+	}
+	return false, false
+}
+
+func isControlClass(c graphemeClass) bool {
+	return c == gcCR || c == gcLF || c == gcControl
+}
+
+// hangulRules returns true if the rules GB6 to GB8 keep a rune of class p
+// and a rune of class c in the same Hangul syllable.
+func hangulRules(p, c graphemeClass) bool {
+	switch p {
+	case gcL:
+		return c == gcL || c == gcV || c == gcLV || c == gcLVT
+	case gcLV, gcV:
+		return c == gcV || c == gcT
+	case gcLVT, gcT:
+		return c == gcT
+	}
+	return false
+}
+
+// scanGraphemes is a bufio.SplitFunc that returns extended grapheme clusters.
+func scanGraphemes(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	r, width, err := decodeRune(data, atEOF)
+	if err != nil || width == 0 {
+		return 0, nil, err
+	}
+	var s graphemeState
+	s.next(getGraphemeClass(r))
+	pos := width
+	for {
+		r, width, err = decodeRune(data[pos:], atEOF)
+		if err != nil {
+			return 0, nil, err
+		}
+		if width == 0 {
+			if !atEOF {
+				// we need the following rune to decide, ask for more
+				return 0, nil, nil
+			}
+			break
+		}
+		if s.next(getGraphemeClass(r)) {
+			break
+		}
+		pos += width
+	}
+	return pos, data[:pos], nil
+}
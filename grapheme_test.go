@@ -0,0 +1,76 @@
+package gorkov_test
+
+import (
+	"strings"
+	"testing/iotest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("CharacterTokenizer", func() {
+	DescribeTable("reading one byte at a time",
+		func(input string, expected []Token) {
+			Expect(readAll(NewCharacterTokenizer(iotest.OneByteReader(strings.NewReader(input))))).
+				To(Equal(expected))
+		},
+		Entry(
+			"empty input",
+			"",
+			[]Token{},
+		),
+		Entry(
+			"ASCII",
+			"ab c\n",
+			makeTokens("a", "b", " ", "c", End),
+		),
+		Entry(
+			"combining accents",
+			"e\u0301a\u0323\u0308\n",
+			makeTokens("e\u0301", "a\u0323\u0308", End),
+		),
+		Entry(
+			"emoji ZWJ sequences",
+			"\U0001f468\u200d\U0001f469\u200d\U0001f467x\U0001f3f3\ufe0f\u200d\U0001f308",
+			makeTokens("\U0001f468\u200d\U0001f469\u200d\U0001f467", "x", "\U0001f3f3\ufe0f\u200d\U0001f308"),
+		),
+		Entry(
+			"emoji modifiers",
+			"\U0001f44d\U0001f3fd\U0001f44d",
+			makeTokens("\U0001f44d\U0001f3fd", "\U0001f44d"),
+		),
+		Entry(
+			"flags",
+			"\U0001f1e9\U0001f1ea\U0001f1eb\U0001f1f7\U0001f1ee",
+			makeTokens("\U0001f1e9\U0001f1ea", "\U0001f1eb\U0001f1f7", "\U0001f1ee"),
+		),
+		Entry(
+			"hangul",
+			"\u1100\u1161\u11a8\ud55c\uad6d",
+			makeTokens("\u1100\u1161\u11a8", "\ud55c", "\uad6d"),
+		),
+		Entry(
+			"spacing marks",
+			"\u0915\u093f",
+			makeTokens("\u0915\u093f"),
+		),
+		Entry(
+			"a ZWJ without a pictograph",
+			"a\u200d\U0001f44d",
+			makeTokens("a\u200d", "\U0001f44d"),
+		),
+		Entry(
+			"mixed line endings",
+			"a\r\nb\rc\u2028\r\n",
+			makeTokens("a", End, "b", End, "c", End, End),
+		),
+		Entry(
+			"combining marks after newlines",
+			"\n\u0301",
+			makeTokens(End, "\u0301"),
+		),
+	)
+})
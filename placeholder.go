@@ -0,0 +1,128 @@
+package gorkov
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+const (
+	// PlaceholderType is the type used for placeholder tokens.
+	PlaceholderType = "p"
+)
+
+// Classes of placeholder tokens used by this package. Any other string that
+// does not contain null bytes can be used as a class as well.
+const (
+	NumberClass   = "NUMBER"
+	UsernameClass = "USERNAME"
+	URLClass      = "URL"
+	DateClass     = "DATE"
+	EmailClass    = "EMAIL"
+	PhoneClass    = "PHONE"
+	IPClass       = "IP"
+)
+
+// ValueProvider generates the values of placeholder tokens.
+type ValueProvider interface {
+	// Value returns a new value. It is called every time the Value of a
+	// placeholder token is requested, so it may return a different string
+	// each time.
+	Value() string
+}
+
+// ValueProviderFunc can be used to turn a function into a ValueProvider.
+type ValueProviderFunc func() string
+
+// Value calls f().
+func (f ValueProviderFunc) Value() string {
+	return f()
+}
+
+type placeholder struct {
+	class string
+	p     ValueProvider
+}
+
+func (p placeholder) Type() string {
+	return PlaceholderType
+}
+
+func (p placeholder) Identifier() string {
+	return p.class
+}
+
+func (p placeholder) Value() string {
+	return p.p.Value()
+}
+
+// NewPlaceholder creates a new placeholder token. Placeholder tokens stand in
+// for a class of values, such as numbers or user names. The class is used as
+// the identifier, so all placeholders of the same class are considered equal,
+// regardless of their ValueProvider. The value is generated by p every time
+// Value is called.
+func NewPlaceholder(class string, p ValueProvider) Token {
+	return placeholder{
+		class: class,
+		p:     p,
+	}
+}
+
+// StaticValue returns a ValueProvider that always returns value.
+func StaticValue(value string) ValueProvider {
+	return ValueProviderFunc(func() string {
+		return value
+	})
+}
+
+// RandomNumber returns a ValueProvider that returns a random integer n with
+// min <= n <= max, formatted in base 10. It panics if max < min. r must not be
+// used concurrently elsewhere.
+func RandomNumber(r *rand.Rand, min, max int) ValueProvider {
+	if max < min {
+		panic("gorkov: invalid range for RandomNumber")
+	}
+	// The arithmetic is done in 64 bits, wrapping around on overflow, so
+	// that ranges wider than the largest int work as well.
+	span := uint64(int64(max) - int64(min))
+	return ValueProviderFunc(func() string {
+		return strconv.FormatInt(int64(min)+int64(randomUint64(r, span)), 10)
+	})
+}
+
+// randomUint64 returns a uniformly distributed random integer n with
+// 0 <= n <= max.
+func randomUint64(r *rand.Rand, max uint64) uint64 {
+	if max == math.MaxUint64 {
+		return r.Uint64()
+	}
+	n := max + 1
+	// reject values from the incomplete last block of n values
+	limit := math.MaxUint64 - (math.MaxUint64%n+1)%n
+	for {
+		if v := r.Uint64(); v <= limit {
+			return v % n
+		}
+	}
+}
+
+// RandomChoice returns a ValueProvider that returns a random element of
+// values. It panics if values is empty. r must not be used concurrently
+// elsewhere.
+func RandomChoice(r *rand.Rand, values ...string) ValueProvider {
+	if len(values) == 0 {
+		panic("gorkov: no values given to RandomChoice")
+	}
+	return ValueProviderFunc(func() string {
+		return values[r.Intn(len(values))]
+	})
+}
+
+// CurrentDate returns a ValueProvider that returns the current local time
+// formatted according to layout. See time.Time.Format for details.
+func CurrentDate(layout string) ValueProvider {
+	return ValueProviderFunc(func() string {
+		return time.Now().Format(layout)
+	})
+}
@@ -0,0 +1,91 @@
+package gorkov_test
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("Placeholder", func() {
+	var (
+		number  Token
+		number2 Token
+		name    Token
+	)
+
+	BeforeEach(func() {
+		r := rand.New(rand.NewSource(1))
+		number = NewPlaceholder(NumberClass, RandomNumber(r, 10, 12))
+		number2 = NewPlaceholder(NumberClass, StaticValue("42"))
+		name = NewPlaceholder(UsernameClass, RandomChoice(r, "alice", "bob"))
+	})
+
+	It("should use PlaceholderType and the class as identifier", func() {
+		Expect(number.Type()).To(Equal(PlaceholderType))
+		Expect(number.Identifier()).To(Equal(NumberClass))
+		Expect(name.Identifier()).To(Equal(UsernameClass))
+	})
+
+	It("should be equal to placeholders of the same class only", func() {
+		Expect(TokensEqual(number, number2)).To(BeTrue())
+		Expect(TokensEqual(number, name)).To(BeFalse())
+		Expect(TokensEqual(number, Literal(NumberClass))).To(BeFalse())
+	})
+
+	Describe("RandomNumber", func() {
+		It("should generate numbers in the range", func() {
+			seen := map[string]bool{}
+			for i := 0; i < 100; i++ {
+				seen[number.Value()] = true
+			}
+			Expect(seen).To(Equal(map[string]bool{"10": true, "11": true, "12": true}))
+		})
+		It("should generate numbers in ranges wider than the largest int", func() {
+			maxInt := int(^uint(0) >> 1)
+			minInt := -maxInt - 1
+			r := rand.New(rand.NewSource(1))
+			for _, bounds := range [][2]int{{0, maxInt}, {minInt, maxInt}, {minInt, -1}, {maxInt, maxInt}} {
+				n, err := strconv.Atoi(RandomNumber(r, bounds[0], bounds[1]).Value())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(n).To(BeNumerically(">=", bounds[0]))
+				Expect(n).To(BeNumerically("<=", bounds[1]))
+			}
+		})
+		It("should panic on an invalid range", func() {
+			Expect(func() { RandomNumber(rand.New(rand.NewSource(1)), 2, 1) }).To(Panic())
+		})
+	})
+
+	Describe("RandomChoice", func() {
+		It("should generate values from the list", func() {
+			seen := map[string]bool{}
+			for i := 0; i < 100; i++ {
+				seen[name.Value()] = true
+			}
+			Expect(seen).To(Equal(map[string]bool{"alice": true, "bob": true}))
+		})
+		It("should panic without values", func() {
+			Expect(func() { RandomChoice(rand.New(rand.NewSource(1))) }).To(Panic())
+		})
+	})
+
+	Describe("StaticValue", func() {
+		It("should always return the value", func() {
+			Expect(number2.Value()).To(Equal("42"))
+			Expect(number2.Value()).To(Equal("42"))
+		})
+	})
+
+	Describe("CurrentDate", func() {
+		It("should return the current date", func() {
+			date := NewPlaceholder(DateClass, CurrentDate("2006"))
+			Expect(date.Identifier()).To(Equal(DateClass))
+			Expect(date.Value()).To(Equal(strconv.Itoa(time.Now().Year())))
+		})
+	})
+})
@@ -0,0 +1,215 @@
+package gorkov
+
+import (
+	"net"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// redactEmailPattern matches e-mail addresses. Unlike emailPattern, it
+// accepts any domain, including single labels like "localhost" and labels
+// containing non-ASCII letters.
+const redactEmailPattern = `[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)*`
+
+// redactPattern matches candidates for personally identifiable information.
+// The submatches are, in order, e-mail addresses, IPv4 addresses, IPv6
+// addresses and phone numbers. Candidates still have to be validated, see
+// matchPII.
+var redactPattern = regexp.MustCompile(`(?i)` +
+	`(` + redactEmailPattern + `)|` +
+	`((?:\d{1,3}\.){3}\d{1,3})|` +
+	`([0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7})|` +
+	`((?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\d{2,4}(?:[ .-]?\d{2,4}){1,4})`)
+
+// redactMaxSpan is an upper bound for the length in bytes of the phone number
+// candidates matched by redactPattern, the only candidates that may contain
+// whitespace.
+const redactMaxSpan = 64
+
+// datePattern matches text starting with a date like 2006-01-02 or
+// 02.01.2006, optionally followed by a space and more text like a time, and
+// yearsPattern matches lists of years like "1990 2000 2010". Both would
+// otherwise be mistaken for phone numbers.
+var (
+	datePattern = regexp.MustCompile(
+		`^(?:\d{4}-(?:0?[1-9]|1[0-2])-(?:0?[1-9]|[12]\d|3[01])|\d{1,2}[./-]\d{1,2}[./-]\d{4})(?:$| )`)
+	yearsPattern = regexp.MustCompile(`^(?:19|20)\d\d(?:[ .-](?:19|20)\d\d)+$`)
+)
+
+// Redactor is a Tokenizer that removes personally identifiable information
+// from a stream of tokens. It replaces e-mail addresses, phone numbers and IP
+// addresses with placeholder tokens of the classes EmailClass, PhoneClass and
+// IPClass. E-mail addresses are replaced if they are syntactically valid,
+// whatever their domain. Tokens of type EmailType, as returned by
+// RecognizeEntities, are replaced as well. Tokens of type URLType are
+// replaced with placeholder tokens of the class URLClass if they contain user
+// information or any personally identifiable information, for example an IP
+// address as host or an e-mail address in the query.
+//
+// Phone numbers may contain whitespace, so a Redactor buffers literal tokens
+// until their text can no longer be part of a phone number. Like
+// RecognizeEntities, literal tokens that are only partially redacted are
+// split and all other tokens are returned unmodified.
+type Redactor struct {
+	t         *entityTokenizer
+	providers map[string]ValueProvider
+	counts    map[string]int
+}
+
+// NewRedactor creates a new Redactor reading from t. The values of the
+// placeholder tokens are generated by the ValueProvider for their class in
+// providers. If there is none, the class enclosed in angle brackets is used,
+// for example "<EMAIL>". providers may be nil.
+func NewRedactor(t Tokenizer, providers map[string]ValueProvider) *Redactor {
+	r := &Redactor{
+		providers: providers,
+		counts:    make(map[string]int),
+	}
+	r.t = &entityTokenizer{
+		t:          t,
+		match:      r.match,
+		spanSpaces: true,
+		maxSpan:    redactMaxSpan,
+		found:      r.count,
+	}
+	return r
+}
+
+// Next returns the next token with all personally identifiable information
+// replaced.
+func (r *Redactor) Next() (Token, error) {
+	t, err := r.t.Next()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case t.Type() == EmailType:
+		t = r.placeholder(EmailClass)
+		r.count(t)
+	case t.Type() == URLType && identifyingURL(t.Value()):
+		t = r.placeholder(URLClass)
+		r.count(t)
+	}
+	return t, nil
+}
+
+// Redacted returns how many tokens of each class have been redacted so far.
+// This may include tokens that have been buffered but not yet been returned
+// by Next. The result is complete once Next returned an error.
+func (r *Redactor) Redacted() map[string]int {
+	result := make(map[string]int, len(r.counts))
+	for class, n := range r.counts {
+		result[class] = n
+	}
+	return result
+}
+
+func (r *Redactor) count(t Token) {
+	r.counts[t.Identifier()]++
+}
+
+func (r *Redactor) placeholder(class string) Token {
+	p, ok := r.providers[class]
+	if !ok {
+		p = StaticValue("<" + class + ">")
+	}
+	return NewPlaceholder(class, p)
+}
+
+// match is an entityMatcher for e-mail addresses, IP addresses and phone
+// numbers.
+func (r *Redactor) match(s string, i int) (int, int, Token) {
+	start, end, class := matchPII(s, i)
+	if class == "" {
+		return 0, 0, nil
+	}
+	return start, end, r.placeholder(class)
+}
+
+// matchPII returns the bounds and class of the first piece of personally
+// identifiable information in s that starts at or after the byte offset i.
+// If there is none, the class is empty.
+func matchPII(s string, i int) (int, int, string) {
+	for i < len(s) {
+		m := redactPattern.FindStringSubmatchIndex(s[i:])
+		if m == nil {
+			break
+		}
+		start, end := i+m[0], i+m[1]
+		if class := classifyPII(s, start, end, m); class != "" {
+			return start, end, class
+		}
+		_, width := utf8.DecodeRuneInString(s[start:])
+		i = start + width
+	}
+	return 0, 0, ""
+}
+
+// classifyPII checks the candidate s[start:end] matched by redactPattern with
+// the submatch indices m. If it is valid, its class is returned. Otherwise the
+// class is empty.
+func classifyPII(s string, start, end int, m []int) string {
+	switch {
+	case m[2] >= 0:
+		return EmailClass
+	case (m[4] >= 0 || m[6] >= 0) && isolatedNumber(s, start, end) && validIP(s[start:end]):
+		return IPClass
+	case m[8] >= 0 && isolatedNumber(s, start, end) && validPhone(s[start:end]):
+		return PhoneClass
+	}
+	return ""
+}
+
+// identifyingURL returns true if url contains user information or any
+// personally identifiable information as found by matchPII.
+func identifyingURL(url string) bool {
+	userinfo, _ := urlAuthority(url)
+	_, _, class := matchPII(url, 0)
+	return userinfo != "" || class != ""
+}
+
+// isolatedNumber returns true if s[start:end] is not part of a larger number
+// or word. That is, it is neither adjacent to a letter or digit nor to a
+// separator like '.' that is in turn adjacent to a digit.
+func isolatedNumber(s string, start, end int) bool {
+	if followsWord(s, start) {
+		return false
+	}
+	prev, width := utf8.DecodeLastRuneInString(s[:start])
+	if before, _ := utf8.DecodeLastRuneInString(s[:start-width]); isNumberSeparator(prev) && unicode.IsDigit(before) {
+		return false
+	}
+	next, width := utf8.DecodeRuneInString(s[end:])
+	if isAlnum(next) || next == '_' {
+		return false
+	}
+	after, _ := utf8.DecodeRuneInString(s[end+width:])
+	return !isNumberSeparator(next) || !unicode.IsDigit(after)
+}
+
+func isNumberSeparator(r rune) bool {
+	return r == '.' || r == ',' || r == '-'
+}
+
+// validIP returns true if ip is an IP address other than "::".
+func validIP(ip string) bool {
+	return strings.Trim(ip, ":") != "" && net.ParseIP(ip) != nil
+}
+
+// validPhone returns true if the phone number candidate phone contains
+// between 7 and 15 digits, the maximum allowed by ITU-T E.164, and neither
+// starts with a date nor looks like a list of years.
+func validPhone(phone string) bool {
+	if datePattern.MatchString(phone) || yearsPattern.MatchString(phone) {
+		return false
+	}
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
@@ -0,0 +1,156 @@
+package gorkov_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("Redactor", func() {
+	var (
+		email = NewPlaceholder(EmailClass, StaticValue("<EMAIL>"))
+		phone = NewPlaceholder(PhoneClass, StaticValue("<PHONE>"))
+		ip    = NewPlaceholder(IPClass, StaticValue("<IP>"))
+	)
+
+	DescribeTable("reading from a ReaderTokenizer",
+		func(input string, expected []Token, counts map[string]int) {
+			redactor := NewRedactor(NewTokenizer(strings.NewReader(input)), nil)
+			tokens := readAll(redactor)
+			Expect(tokens).To(HaveLen(len(expected)))
+			for i, t := range expected {
+				Expect(TokensEqual(t, tokens[i])).To(BeTrue(), "%q should equal %q", t, tokens[i])
+				Expect(tokens[i].Value()).To(Equal(t.Value()))
+			}
+			Expect(redactor.Redacted()).To(Equal(counts))
+		},
+		Entry(
+			"nothing to redact",
+			"foo 12, 3.14159265 and 2017-12-24\n",
+			makeTokens("foo", " ", "12", ", ", "3", ".", "14159265", " ", "and", " ", "2017", "-", "12", "-", "24", End),
+			map[string]int{},
+		),
+		Entry(
+			"dates and years",
+			"am 24.12.2017 and 1990 2000 2010\n",
+			makeTokens("am", " ", "24", ".", "12", ".", "2017", " ", "and", " ", "1990", " ", "2000", " ", "2010", End),
+			map[string]int{},
+		),
+		Entry(
+			"dates followed by a time",
+			"2017-12-24 10:00 or 24.12.2017 18:30\n",
+			makeTokens("2017", "-", "12", "-", "24", " ", "10", ":", "00", " ", "or", " ",
+				"24", ".", "12", ".", "2017", " ", "18", ":", "30", End),
+			map[string]int{},
+		),
+		Entry(
+			"e-mail addresses",
+			"mail gopher@golang.org or foo@bar.invalidtld\n",
+			makeTokens("mail", " ", email, " ", "or", " ", email, End),
+			map[string]int{EmailClass: 2},
+		),
+		Entry(
+			"e-mail addresses with internal or non-ASCII domains",
+			"alice@corp.internal, dave@host.local, eve@LOCALHOST and bob@bücher.de\n",
+			makeTokens(email, ", ", email, ", ", email, " ", "and", " ", email, End),
+			map[string]int{EmailClass: 4},
+		),
+		Entry(
+			"phone numbers",
+			"call +49 30 1234567, (555) 123-4567 or 555-1234.\n",
+			makeTokens("call", " ", phone, ", ", phone, " ", "or", " ", phone, ".", End),
+			map[string]int{PhoneClass: 3},
+		),
+		Entry(
+			"IP addresses",
+			"from 192.168.0.1 and [2001:db8::1]\nnot 1.2.3.4.5 or 999.1.1.1\n",
+			makeTokens("from", " ", ip, " ", "and", " [", ip, "]", End,
+				"not", " ", "1", ".", "2", ".", "3", ".", "4", ".", "5", " ", "or", " ", "999", ".", "1", ".", "1", ".", "1", End),
+			map[string]int{IPClass: 2},
+		),
+		Entry(
+			"entities in a single token",
+			"id:192.168.0.1\n",
+			makeTokens("id", ":", ip, End),
+			map[string]int{IPClass: 1},
+		),
+	)
+	Describe("reading from RecognizeEntities", func() {
+		It("should redact e-mail tokens", func() {
+			redactor := NewRedactor(RecognizeEntities(NewTokenizer(strings.NewReader("gopher@golang.org"))), nil)
+			tokens := readAll(redactor)
+			Expect(tokens).To(HaveLen(1))
+			Expect(TokensEqual(tokens[0], email)).To(BeTrue())
+			Expect(redactor.Redacted()).To(Equal(map[string]int{EmailClass: 1}))
+		})
+		It("should redact URL tokens with user information or IP addresses", func() {
+			input := "http://192.168.0.1/admin or http://bob@example.com/ and https://golang.org/"
+			redactor := NewRedactor(RecognizeEntities(NewTokenizer(strings.NewReader(input))), nil)
+			url := NewPlaceholder(URLClass, StaticValue("<URL>"))
+			tokens := readAll(redactor)
+			Expect(tokens).To(HaveLen(9))
+			Expect(TokensEqual(tokens[0], url)).To(BeTrue())
+			Expect(TokensEqual(tokens[4], url)).To(BeTrue())
+			Expect(TokensEqual(tokens[8], NewToken(URLType, "https://golang.org/"))).To(BeTrue())
+			Expect(redactor.Redacted()).To(Equal(map[string]int{URLClass: 2}))
+		})
+		It("should redact URL tokens with e-mail addresses or phone numbers in the query", func() {
+			input := "https://example.com/contact?mail=joe@corp.com&tel=555-123-4567 https://example.com/?tel=555-123-4567"
+			redactor := NewRedactor(RecognizeEntities(NewTokenizer(strings.NewReader(input))), nil)
+			url := NewPlaceholder(URLClass, StaticValue("<URL>"))
+			tokens := readAll(redactor)
+			Expect(tokens).To(HaveLen(3))
+			Expect(TokensEqual(tokens[0], url)).To(BeTrue())
+			Expect(TokensEqual(tokens[2], url)).To(BeTrue())
+			Expect(redactor.Redacted()).To(Equal(map[string]int{URLClass: 2}))
+		})
+	})
+	Describe("reading a long line", func() {
+		It("should return tokens before the end of the line", func() {
+			read := 0
+			source := Tee(NewTokenizer(strings.NewReader(strings.Repeat("call 555-1234 ", 100))), func(Token) error {
+				read++
+				return nil
+			})
+			redactor := NewRedactor(source, nil)
+			tokens := []Token{}
+			for len(tokens) < 4 {
+				t, err := redactor.Next()
+				Expect(err).NotTo(HaveOccurred())
+				tokens = append(tokens, t)
+			}
+			Expect(TokensEqual(tokens[2], phone)).To(BeTrue())
+			Expect(read).To(BeNumerically("<", 100))
+		})
+		It("should return tokens before the end of a line without whitespace", func() {
+			for _, wrap := range []func(Tokenizer) Tokenizer{
+				func(t Tokenizer) Tokenizer { return NewRedactor(t, nil) },
+				RecognizeEntities,
+			} {
+				read := 0
+				source := Tee(NewTokenizer(strings.NewReader(strings.Repeat("a,", 100000))), func(Token) error {
+					read++
+					return nil
+				})
+				token, err := wrap(source).Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(token).To(Equal(Literal("a")))
+				Expect(read).To(BeNumerically("<", 200000))
+			}
+		})
+	})
+	Describe("using custom providers", func() {
+		It("should use the provider for the class", func() {
+			redactor := NewRedactor(NewTokenizer(strings.NewReader("10.0.0.1 gopher@golang.org")),
+				map[string]ValueProvider{IPClass: StaticValue("127.0.0.1")})
+			tokens := readAll(redactor)
+			Expect(tokens).To(HaveLen(3))
+			Expect(tokens[0].Value()).To(Equal("127.0.0.1"))
+			Expect(tokens[2].Value()).To(Equal("<EMAIL>"))
+		})
+	})
+})
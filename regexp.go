@@ -0,0 +1,163 @@
+package gorkov
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"unicode/utf8"
+)
+
+// regexpLookahead is the number of bytes a RegexpTokenizer reads ahead before
+// matching its rules, unless the end of the input has been reached.
+const regexpLookahead = 4096
+
+// Rule describes how to turn text matching a regular expression into tokens.
+type Rule struct {
+	// Name identifies the rule in error messages.
+	Name string
+
+	// Pattern is a regular expression in the syntax accepted by the regexp
+	// package. It is matched at the current position of the input only.
+	Pattern string
+
+	// Type is the type of the tokens created for matches. Both the
+	// identifier and the value of these tokens are the matched text. If Type
+	// is the type of End, End is returned instead. If Type is empty,
+	// LiteralType is used.
+	Type string
+}
+
+// Rules is a compiled, ordered list of Rule values that can be used by any
+// number of RegexpTokenizers.
+type Rules struct {
+	patterns []*regexp.Regexp
+	types    []string
+	fallback string
+}
+
+// CompileRules compiles rules for use by a RegexpTokenizer. Text that is not
+// matched by any rule is returned as tokens of type fallback. If fallback is
+// empty, LiteralType is used.
+func CompileRules(fallback string, rules ...Rule) (*Rules, error) {
+	result := &Rules{
+		patterns: make([]*regexp.Regexp, len(rules)),
+		types:    make([]string, len(rules)),
+		fallback: orLiteralType(fallback),
+	}
+	for i, rule := range rules {
+		p, err := regexp.Compile(`^(?:` + rule.Pattern + `)`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for rule %q: %v", rule.Name, err)
+		}
+		result.patterns[i] = p
+		result.types[i] = orLiteralType(rule.Type)
+	}
+	return result, nil
+}
+
+// MustCompileRules is like CompileRules but panics if a pattern cannot be
+// compiled.
+func MustCompileRules(fallback string, rules ...Rule) *Rules {
+	result, err := CompileRules(fallback, rules...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func orLiteralType(t string) string {
+	if t == "" {
+		return LiteralType
+	}
+	return t
+}
+
+// match returns the length of the match of the first rule that matches a
+// non-empty prefix of data and the type of that rule. If no rule matches, a
+// length of 0 is returned.
+func (r *Rules) match(data []byte) (int, string) {
+	for i, p := range r.patterns {
+		if loc := p.FindIndex(data); loc != nil && loc[1] > 0 {
+			return loc[1], r.types[i]
+		}
+	}
+	return 0, ""
+}
+
+// RegexpTokenizer turns data from an io.Reader into a stream of tokens using
+// Rules. At every position of the input the rules are tried in order and the
+// first rule that matches a non-empty text determines the next token. Text
+// that no rule matches is collected until a rule matches again and returned
+// as a single token of the fallback type.
+//
+// A RegexpTokenizer does not read the whole input into memory. Instead it
+// reads ahead at least 4 KiB before matching, unless the end of the input is
+// reached earlier, so matches that depend on more than that many bytes
+// following the current position may differ. A single token may not be
+// longer than bufio.MaxScanTokenSize.
+type RegexpTokenizer struct {
+	s *bufio.Scanner
+	// t is the type of the token last returned by the split function.
+	t string
+}
+
+// NewRegexpTokenizer creates a new RegexpTokenizer reading from r using the
+// given rules.
+func NewRegexpTokenizer(r io.Reader, rules *Rules) *RegexpTokenizer {
+	t := &RegexpTokenizer{s: bufio.NewScanner(r)}
+	t.s.Split(t.split(rules))
+	return t
+}
+
+// Next returns the next token. See the description of RegexpTokenizer for an
+// explanation of which kind of tokens to expect.
+func (t *RegexpTokenizer) Next() (Token, error) {
+	if !t.s.Scan() {
+		if t.s.Err() == nil {
+			return nil, io.EOF
+		}
+		return nil, t.s.Err()
+	}
+	if t.t == End.Type() {
+		return End, nil
+	}
+	return NewToken(t.t, t.s.Text()), nil
+}
+
+// split returns a bufio.SplitFunc that splits its input according to rules
+// and stores the type of each token in t.t.
+func (t *RegexpTokenizer) split(rules *Rules) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if !atEOF && len(data) < regexpLookahead {
+			return 0, nil, nil
+		}
+		n, typ := rules.match(data)
+		if n == 0 {
+			n, typ = unmatched(rules, data), rules.fallback
+		}
+		if n == len(data) && !atEOF {
+			// the token might continue, ask for more
+			return 0, nil, nil
+		}
+		t.t = typ
+		return n, data[:n], nil
+	}
+}
+
+// unmatched returns the length of the prefix of data that is not matched by
+// any rule. It is at least one rune long.
+func unmatched(rules *Rules, data []byte) int {
+	_, pos := utf8.DecodeRune(data)
+	for pos < len(data) {
+		if n, _ := rules.match(data[pos:]); n > 0 {
+			break
+		}
+		_, width := utf8.DecodeRune(data[pos:])
+		pos += width
+	}
+	return pos
+}
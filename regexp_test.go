@@ -0,0 +1,76 @@
+package gorkov_test
+
+import (
+	"strings"
+	"testing/iotest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("RegexpTokenizer", func() {
+	rules := MustCompileRules("x",
+		Rule{Name: "word", Pattern: `\pL+(?:'\pL+)*`},
+		Rule{Name: "number", Pattern: `\d+(?:\.\d+)?`, Type: "n"},
+		Rule{Name: "emoticon", Pattern: `[:;]-?[()DP]`, Type: "s"},
+		Rule{Name: "space", Pattern: `[ \t]+`, Type: "w"},
+		Rule{Name: "newline", Pattern: `\r?\n`, Type: End.Type()},
+		Rule{Name: "empty", Pattern: `q*`, Type: "never"},
+	)
+
+	DescribeTable("reading one byte at a time",
+		func(input string, expected []Token) {
+			Expect(readAll(NewRegexpTokenizer(iotest.OneByteReader(strings.NewReader(input)), rules))).
+				To(Equal(expected))
+		},
+		Entry(
+			"empty input",
+			"",
+			[]Token{},
+		),
+		Entry(
+			"words, numbers and emoticons",
+			"don't pay 3.50 :-)\r\n",
+			[]Token{Literal("don't"), NewToken("w", " "), Literal("pay"), NewToken("w", " "), NewToken("n", "3.50"),
+				NewToken("w", " "), NewToken("s", ":-)"), End},
+		),
+		Entry(
+			"unmatched text",
+			"a,, ?!b:",
+			[]Token{Literal("a"), NewToken("x", ",,"), NewToken("w", " "), NewToken("x", "?!"), Literal("b"),
+				NewToken("x", ":")},
+		),
+	)
+
+	Describe("reading long input", func() {
+		It("should match tokens across buffer boundaries", func() {
+			line := strings.Repeat("ab 12.5 ", 1000) + "\n"
+			tokens := readAll(NewRegexpTokenizer(iotest.HalfReader(strings.NewReader(line+line)), rules))
+			Expect(tokens).To(HaveLen(2 * 4001))
+			for i, t := range tokens {
+				switch j := i % 4001; {
+				case j == 4000:
+					Expect(t).To(Equal(End))
+				case j%2 == 1:
+					Expect(t).To(Equal(NewToken("w", " ")))
+				case j%4 == 0:
+					Expect(t).To(Equal(Literal("ab")), "token %d", i)
+				default:
+					Expect(t).To(Equal(NewToken("n", "12.5")), "token %d", i)
+				}
+			}
+		})
+	})
+
+	Describe("compiling invalid rules", func() {
+		It("should return an error mentioning the rule", func() {
+			_, err := CompileRules("", Rule{Name: "broken", Pattern: `(`})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("broken"))
+			Expect(func() { MustCompileRules("", Rule{Name: "broken", Pattern: `(`}) }).To(Panic())
+		})
+	})
+})
@@ -0,0 +1,122 @@
+package gorkov
+
+import (
+	"unicode/utf8"
+)
+
+// Segmenter splits words of scripts that do not separate words with spaces,
+// such as Chinese, Japanese or Thai.
+type Segmenter interface {
+	// Segment splits text into words. Concatenating the returned words must
+	// result in text again. Text that cannot or need not be split has to be
+	// returned as a single word.
+	Segment(text string) []string
+}
+
+// SegmenterFunc can be used to turn a function into a Segmenter.
+type SegmenterFunc func(text string) []string
+
+// Segment calls f(text).
+func (f SegmenterFunc) Segment(text string) []string {
+	return f(text)
+}
+
+// DictionarySegmenter is a Segmenter that splits text into words from a
+// dictionary. It uses greedy longest matching: starting at the beginning of
+// the text, it always picks the longest word from the dictionary that matches.
+// Runs of runes that do not start any word from the dictionary are returned as
+// one word, so text in other scripts is left untouched.
+type DictionarySegmenter struct {
+	words map[string]bool
+	// maxRunes is the length of the longest word in runes.
+	maxRunes int
+}
+
+// NewDictionarySegmenter creates a new DictionarySegmenter using the given
+// words as its dictionary.
+func NewDictionarySegmenter(words []string) *DictionarySegmenter {
+	d := &DictionarySegmenter{words: make(map[string]bool, len(words))}
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		d.words[w] = true
+		if n := utf8.RuneCountInString(w); n > d.maxRunes {
+			d.maxRunes = n
+		}
+	}
+	return d
+}
+
+// Segment splits text into words as described in the documentation of
+// DictionarySegmenter.
+func (d *DictionarySegmenter) Segment(text string) []string {
+	var (
+		result  []string
+		unknown = 0
+	)
+	for pos := 0; pos < len(text); {
+		if n := d.match(text[pos:]); n > 0 {
+			if unknown < pos {
+				result = append(result, text[unknown:pos])
+			}
+			result = append(result, text[pos:pos+n])
+			pos += n
+			unknown = pos
+			continue
+		}
+		_, width := utf8.DecodeRuneInString(text[pos:])
+		pos += width
+	}
+	if unknown < len(text) {
+		result = append(result, text[unknown:])
+	}
+	return result
+}
+
+// match returns the length in bytes of the longest word from the dictionary
+// that text starts with, or 0 if there is none.
+func (d *DictionarySegmenter) match(text string) int {
+	// ends contains the offsets at which the first d.maxRunes runes end.
+	ends := make([]int, 0, d.maxRunes)
+	for i := range text {
+		if i > 0 {
+			ends = append(ends, i)
+		}
+		if len(ends) == d.maxRunes {
+			break
+		}
+	}
+	if len(ends) < d.maxRunes {
+		ends = append(ends, len(text))
+	}
+	for i := len(ends) - 1; i >= 0; i-- {
+		if d.words[text[:ends[i]]] {
+			return ends[i]
+		}
+	}
+	return 0
+}
+
+// segment wraps a Tokenizer. The new Tokenizer returns the same stream of
+// tokens as the original, except that literal tokens that do not start with
+// whitespace, punctuation or one of the given newlines are split using s.
+func segment(t Tokenizer, s Segmenter, newlines string) Tokenizer {
+	var queue []string
+	return TokenizerFunc(func() (Token, error) {
+		for len(queue) == 0 {
+			token, err := t.Next()
+			if err != nil {
+				return nil, err
+			}
+			r, _ := utf8.DecodeRuneInString(token.Value())
+			if token.Type() != LiteralType || getRuneType(r, newlines) != runeLiteral {
+				return token, nil
+			}
+			queue = s.Segment(token.Value())
+		}
+		word := queue[0]
+		queue = queue[1:]
+		return Literal(word), nil
+	})
+}
@@ -0,0 +1,34 @@
+package gorkov_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("DictionarySegmenter", func() {
+	segmenter := NewDictionarySegmenter([]string{"สวัสดี", "ครับ", "ภาษา", "ภาษาไทย", "中国", "中国人", "人", ""})
+
+	DescribeTable("segmenting text",
+		func(text string, expected []string) {
+			Expect(segmenter.Segment(text)).To(Equal(expected))
+		},
+		Entry("empty text", "", []string(nil)),
+		Entry("a single word", "ครับ", []string{"ครับ"}),
+		Entry("multiple words", "สวัสดีครับ", []string{"สวัสดี", "ครับ"}),
+		Entry("the longest match", "ภาษาไทยครับ", []string{"ภาษาไทย", "ครับ"}),
+		Entry("unknown words", "foo中国人bar人", []string{"foo", "中国人", "bar", "人"}),
+		Entry("only unknown words", "hello", []string{"hello"}),
+	)
+
+	Describe("used by a WordTokenizer", func() {
+		It("should split literal tokens", func() {
+			tokenizer := NewWordTokenizer(strings.NewReader("สวัสดีครับ, 中国人!\n"), WordOptions{Segmenter: segmenter})
+			Expect(readAll(tokenizer)).To(Equal(makeTokens("สวัสดี", "ครับ", ", ", "中国人", "!", End)))
+		})
+	})
+})
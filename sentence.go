@@ -0,0 +1,239 @@
+package gorkov
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultAbbreviations is the list of abbreviations used if
+// WordOptions.Abbreviations is nil.
+var DefaultAbbreviations = []string{
+	"e.g.", "i.e.", "etc.", "vs.", "cf.", "approx.",
+	"mr.", "mrs.", "ms.", "dr.", "prof.", "jr.", "sr.",
+	"jan.", "feb.", "mar.", "apr.", "jun.", "jul.", "aug.", "sep.", "sept.", "oct.", "nov.", "dec.",
+}
+
+// terminalPunctuation contains the runes that can end a sentence.
+const terminalPunctuation = ".!?…"
+
+// sentenceTokenizer wraps a Tokenizer that returns literal tokens as created
+// by scanWords. It turns newlines into End tokens and, depending on its
+// options, ends sentences and joins lines.
+type sentenceTokenizer struct {
+	t             Tokenizer
+	sentences     bool
+	joinLines     bool
+	newlineRunes  string
+	abbreviations map[string]bool
+
+	// run contains the text since the last whitespace.
+	run string
+	// atEnd is true if no token has been returned since the last End, or
+	// if nothing has been returned yet.
+	atEnd bool
+	// space is true if a newline has been joined and a space still has to
+	// be queued in its place.
+	space bool
+	// lineStart is true if only whitespace has been read since the last
+	// newline. If lines are joined, that whitespace is held back in indent
+	// until it is known whether the line is blank.
+	lineStart bool
+	indent    string
+	err       error
+	queue []Token
+}
+
+// newSentenceTokenizer creates a new sentenceTokenizer reading from t using
+// the sentence options of o.
+func newSentenceTokenizer(t Tokenizer, o WordOptions) *sentenceTokenizer {
+	abbreviations := o.Abbreviations
+	if abbreviations == nil {
+		abbreviations = DefaultAbbreviations
+	}
+	s := &sentenceTokenizer{
+		t:             t,
+		sentences:     o.Sentences,
+		joinLines:     o.JoinLines,
+		newlineRunes:  o.newlines(),
+		abbreviations: make(map[string]bool, len(abbreviations)),
+		atEnd:         true,
+	}
+	for _, a := range abbreviations {
+		s.abbreviations[strings.ToLower(a)] = true
+	}
+	return s
+}
+
+// Next returns the next token. If lines are joined, a token consisting of
+// whitespace and punctuation is only returned once the following token is
+// known, so that the space replacing a newline can be merged into it.
+func (s *sentenceTokenizer) Next() (Token, error) {
+	for s.err == nil && (len(s.queue) == 0 || s.joinLines && s.lastSeparator() != "") {
+		token, err := s.t.Next()
+		if err != nil {
+			s.err = err
+			s.finish()
+			break
+		}
+		s.process(token.Value())
+	}
+	if len(s.queue) == 0 {
+		return nil, s.err
+	}
+	t := s.queue[0]
+	s.queue = s.queue[1:]
+	return t, nil
+}
+
+// finish is called once the underlying Tokenizer returned an error. If it is
+// io.EOF and the input ends in a joined newline, the newline ends the last
+// paragraph instead.
+func (s *sentenceTokenizer) finish() {
+	if s.err == io.EOF && s.space {
+		s.space = false
+		s.end()
+	}
+}
+
+// process queues the tokens resulting from the literal token value.
+func (s *sentenceTokenizer) process(value string) {
+	r, _ := utf8.DecodeRuneInString(value)
+	t := getRuneType(r, s.newlineRunes)
+	switch {
+	case t == runeNewline:
+		s.newlines(countNewlines(value))
+		return
+	case s.joinLines && s.lineStart && strings.TrimSpace(value) == "":
+		s.indent += value
+		return
+	}
+	s.resume(r)
+	if t == runePunctuation {
+		s.punctuation(value)
+	} else {
+		s.run += value
+		s.literal(value)
+	}
+}
+
+// resume queues the text held back at the start of a line before a token
+// starting with r: the whitespace indenting the line or, if there is none,
+// the space replacing a joined newline. Nothing is queued after an End token.
+func (s *sentenceTokenizer) resume(r rune) {
+	switch {
+	case s.atEnd:
+	case s.indent != "":
+		s.literal(s.indent)
+	case s.space && !unicode.IsSpace(r):
+		s.literal(" ")
+	}
+	s.space, s.lineStart, s.indent = false, false, ""
+}
+
+// newlines queues the tokens for n newlines.
+func (s *sentenceTokenizer) newlines(n int) {
+	blank := s.lineStart
+	s.space, s.lineStart, s.indent = false, true, ""
+	switch {
+	case s.joinLines && n == 1 && !blank && !s.sentenceEnds(s.run):
+		last, _ := utf8.DecodeLastRuneInString(s.lastSeparator())
+		s.space = !s.atEnd && !unicode.IsSpace(last)
+	case s.joinLines || s.sentences:
+		s.end()
+	default:
+		for i := 0; i < n; i++ {
+			s.queue = append(s.queue, End)
+		}
+	}
+	s.run = ""
+}
+
+// punctuation queues the tokens for a token consisting of punctuation and
+// whitespace.
+func (s *sentenceTokenizer) punctuation(value string) {
+	w := strings.IndexFunc(value, unicode.IsSpace)
+	if w < 0 {
+		s.run += value
+		s.literal(value)
+		return
+	}
+	if !s.sentenceEnds(s.run + value[:w]) {
+		s.literal(value)
+	} else {
+		if w > 0 {
+			s.literal(value[:w])
+		}
+		s.end()
+		if rest := strings.TrimLeftFunc(value[w:], unicode.IsSpace); rest != "" {
+			s.literal(rest)
+		}
+	}
+	s.run = value[strings.LastIndexFunc(value, unicode.IsSpace)+1:]
+}
+
+// sentenceEnds returns true if sentences are enabled and run, the text since
+// the last whitespace, ends a sentence. That is the case if it contains
+// terminal punctuation that is only followed by other punctuation and is not
+// an abbreviation. Leading punctuation, like an opening parenthesis, is
+// ignored when looking up abbreviations.
+func (s *sentenceTokenizer) sentenceEnds(run string) bool {
+	if !s.sentences {
+		return false
+	}
+	i := strings.LastIndexAny(run, terminalPunctuation)
+	if i < 0 || strings.IndexFunc(run[i:], s.isLiteral) >= 0 {
+		return false
+	}
+	_, width := utf8.DecodeRuneInString(run[i:])
+	word := strings.TrimLeftFunc(run[:i+width], s.isSeparator)
+	return !s.abbreviations[strings.ToLower(word)]
+}
+
+// isLiteral returns true if r is neither whitespace, punctuation nor a
+// newline.
+func (s *sentenceTokenizer) isLiteral(r rune) bool {
+	return getRuneType(r, s.newlineRunes) == runeLiteral
+}
+
+// isSeparator returns true if r is whitespace, punctuation or a newline.
+func (s *sentenceTokenizer) isSeparator(r rune) bool {
+	return !s.isLiteral(r)
+}
+
+// literal queues a literal token. If both value and the last queued token
+// consist of whitespace and punctuation, value is appended to that token
+// instead.
+func (s *sentenceTokenizer) literal(value string) {
+	r, _ := utf8.DecodeRuneInString(value)
+	if sep := s.lastSeparator(); sep != "" && getRuneType(r, s.newlineRunes) == runePunctuation {
+		s.queue[len(s.queue)-1] = Literal(sep + value)
+	} else {
+		s.queue = append(s.queue, Literal(value))
+	}
+	s.atEnd = false
+}
+
+// lastSeparator returns the value of the last queued token if it is a literal
+// consisting of whitespace and punctuation. Otherwise it returns an empty
+// string.
+func (s *sentenceTokenizer) lastSeparator() string {
+	if len(s.queue) == 0 {
+		return ""
+	}
+	t := s.queue[len(s.queue)-1]
+	r, _ := utf8.DecodeRuneInString(t.Value())
+	if t.Type() != LiteralType || getRuneType(r, s.newlineRunes) != runePunctuation {
+		return ""
+	}
+	return t.Value()
+}
+
+// end queues an End token, unless the last token was an End token as well.
+func (s *sentenceTokenizer) end() {
+	if !s.atEnd {
+		s.queue = append(s.queue, End)
+		s.atEnd = true
+	}
+}
@@ -0,0 +1,120 @@
+package gorkov_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("Sentence splitting", func() {
+	DescribeTable("reading with Sentences",
+		func(input string, expected []Token) {
+			o := DefaultWordOptions
+			o.Sentences = true
+			Expect(readAll(NewWordTokenizer(strings.NewReader(input), o))).To(Equal(expected))
+		},
+		Entry(
+			"multiple sentences on one line",
+			"Hi there. How are you? Fine!\n",
+			makeTokens("Hi", " ", "there", ".", End, "How", " ", "are", " ", "you", "?", End, "Fine", "!", End),
+		),
+		Entry(
+			"closing quotes and parentheses",
+			"He said \"no.\" (Really!) Ok",
+			makeTokens("He", " ", "said", " \"", "no", ".\"", End, "(", "Really", "!)", End, "Ok"),
+		),
+		Entry(
+			"punctuation after the whitespace",
+			"Done. \"Next\"",
+			makeTokens("Done", ".", End, "\"", "Next", "\""),
+		),
+		Entry(
+			"abbreviations",
+			"Ask Dr. Who, e.g. today (Prof. X). Ok",
+			makeTokens("Ask", " ", "Dr", ". ", "Who", ", ", "e", ".", "g", ". ", "today", " (", "Prof", ". ",
+				"X", ").", End, "Ok"),
+		),
+		Entry(
+			"numbers",
+			"Pi is 3.14 ok",
+			makeTokens("Pi", " ", "is", " ", "3.14", " ", "ok"),
+		),
+		Entry(
+			"sentences spanning lines",
+			"This is\na sentence.\n\n\nNext\n",
+			makeTokens("This", " ", "is", End, "a", " ", "sentence", ".", End, "Next", End),
+		),
+		Entry(
+			"windows line endings",
+			"One.\r\nTwo\r\n",
+			makeTokens("One", ".", End, "Two", End),
+		),
+		Entry(
+			"leading newlines",
+			"\n\nfoo",
+			makeTokens("foo"),
+		),
+	)
+	DescribeTable("reading with JoinLines",
+		func(input string, sentences bool, expected []Token) {
+			o := DefaultWordOptions
+			o.Sentences = sentences
+			o.JoinLines = true
+			Expect(readAll(NewWordTokenizer(strings.NewReader(input), o))).To(Equal(expected))
+		},
+		Entry(
+			"a paragraph spanning lines",
+			"This is\na paragraph.\n\nNext\n",
+			false,
+			makeTokens("This", " ", "is", " ", "a", " ", "paragraph", ".", End, "Next", End),
+		),
+		Entry(
+			"sentences spanning lines",
+			"This is\na sentence. And\nanother one.\nNext\n\n\nParagraph\n",
+			true,
+			makeTokens("This", " ", "is", " ", "a", " ", "sentence", ".", End, "And", " ", "another", " ", "one", ".", End,
+				"Next", End, "Paragraph", End),
+		),
+		Entry(
+			"abbreviations at the end of lines",
+			"See Dr.\nWho",
+			true,
+			makeTokens("See", " ", "Dr", ". ", "Who"),
+		),
+		Entry(
+			"the last line without terminal punctuation",
+			"This is\nit\n",
+			true,
+			makeTokens("This", " ", "is", " ", "it", End),
+		),
+		Entry(
+			"blank lines containing whitespace",
+			"foo\n  \nbar\n\t \nbaz\n  qux\n",
+			false,
+			makeTokens("foo", End, "bar", End, "baz", "  ", "qux", End),
+		),
+		Entry(
+			"blank lines containing whitespace after a sentence",
+			"It ends.\n \t\nNext one\n",
+			true,
+			makeTokens("It", " ", "ends", ".", End, "Next", " ", "one", End),
+		),
+		Entry(
+			"newlines next to whitespace and punctuation",
+			"This is \nfoo,\nbar\n(baz) \n qux",
+			false,
+			makeTokens("This", " ", "is", " ", "foo", ", ", "bar", " (", "baz", ")  ", "qux"),
+		),
+	)
+	Describe("reading with custom abbreviations", func() {
+		It("should only use the given abbreviations", func() {
+			o := WordOptions{Sentences: true, Abbreviations: []string{"Foo."}}
+			Expect(readAll(NewWordTokenizer(strings.NewReader("FOO. Dr. x"), o))).
+				To(Equal(makeTokens("FOO", ". ", "Dr", ".", End, "x")))
+		})
+	})
+})
@@ -2,8 +2,8 @@ package gorkov
 
 import (
 	"bufio"
-	"fmt"
 	"io"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -24,11 +24,13 @@ func (t TokenizerFunc) Next() (Token, error) {
 	return t()
 }
 
-// ReaderTokenizer turns data from an io.Reader into a stream of tokens. It
-// turns newlines ('\n') into End tokens and returns everything else as literal
-// tokens. Each literal token either only contains whitespace and punctuation or
-// no whitespace and punctuation. Two tokens that follow each other do not
-// contain the same type of characters.
+// ReaderTokenizer turns data from an io.Reader into a stream of tokens. When
+// created using NewTokenizer, it turns newlines into End tokens and returns
+// everything else as literal tokens. Newlines are the runes in
+// DefaultNewlines, with "\r\n" counting as a single newline. Each literal
+// token either only contains whitespace and punctuation or no whitespace and
+// punctuation. Two tokens that follow each other do not contain the same type
+// of characters.
 //
 // Punctuation and whitespace is everything that is a unicode punctuation
 // character (category P) or has Unicode's White Space Property. See the
@@ -40,16 +42,14 @@ type ReaderTokenizer struct {
 
 // NewTokenizer creates a new ReaderTokenizer for the given reader.
 func NewTokenizer(r io.Reader) *ReaderTokenizer {
-	s := bufio.NewScanner(r)
-	s.Split(scanRuneType)
-	return &ReaderTokenizer{s: s}
+	return NewWordTokenizer(r, WordOptions{})
 }
 
 // Next returns the next token. See the description of ReaderTokenizer for an
 // explanation of which kind of tokens to expect.
 func (t *ReaderTokenizer) Next() (Token, error) {
 	if t.t == nil {
-		t.t = newlineToEnd(newScannerTokenizer(t.s))
+		t.t = newlineToEnd(newScannerTokenizer(t.s), DefaultNewlines)
 	}
 	return t.t.Next()
 }
@@ -76,12 +76,12 @@ const (
 )
 
 // getRuneType categorises runes into one of the following categories:
-// runeNewline, runePunctuation or runeLiteral. For '\n' it will always
-// return runeNewline and not runePunctuation. runeLiteral is everything
+// runeNewline, runePunctuation or runeLiteral. For runes in newlines it will
+// always return runeNewline and not runePunctuation. runeLiteral is everything
 // that is neither runeNewline nor runePunctuation.
-func getRuneType(r rune) int {
+func getRuneType(r rune, newlines string) int {
 	switch {
-	case r == '\n':
+	case strings.ContainsRune(newlines, r):
 		return runeNewline
 	case unicode.IsPunct(r) || unicode.IsSpace(r):
 		return runePunctuation
@@ -90,66 +90,35 @@ func getRuneType(r rune) int {
 	}
 }
 
-// scanRuneType is a bufio.SplitFunc. It returns blocks of contiguous runes
-// that have the same type as defined by getRuneType.
-// TODO: simplify this method, its cyclomatic complexity is a bit high.
-//
-// nolint: gocyclo
-func scanRuneType(data []byte, atEOF bool) (int, []byte, error) {
-	if atEOF && len(data) == 0 {
-		return 0, nil, nil
-	}
-	r, width := utf8.DecodeRune(data)
-	if r == utf8.RuneError {
-		if !atEOF && !utf8.FullRune(data) {
-			// not enough bytes for a full rune, ask for more
-			return 0, nil, nil
-		}
-		return 0, nil, fmt.Errorf("invalid UTF8 starting at %X", data)
-	}
-	t, pos := getRuneType(r), width
-	for pos < len(data) {
-		r, width = utf8.DecodeRune(data[pos:])
-		if r == utf8.RuneError {
-			if !atEOF && !utf8.FullRune(data[pos:]) {
-				// not enough bytes for a full rune, ask for more
-				return 0, nil, nil
-			}
-			return 0, nil, fmt.Errorf("invalid UTF8 starting at %X", data)
-		}
-		if getRuneType(r) != t {
-			break
-		}
-		pos += width
-	}
-	if pos == len(data) && !atEOF {
-		return 0, nil, nil
-	}
-	return pos, data[:pos], nil
-}
-
 // newlineToEnd wraps a Tokenizer. The new Tokenizer returns the same stream
 // of tokens as the original except that all newlines are replaced by End
 // tokens.
 //
 // newlineToEnd assumes that the Value of a token is static (it doesn't change
 // between calls) and that a token value either contains no newlines or only
-// newlines. If a token contains x newlines then x End tokens are returned.
-func newlineToEnd(t Tokenizer) Tokenizer {
-	newlines := 0
+// newlines, which are the runes in newlines. If a token contains x newlines
+// then x End tokens are returned. "\r\n" is counted as a single newline.
+func newlineToEnd(t Tokenizer, newlines string) Tokenizer {
+	pending := 0
 	return TokenizerFunc(func() (Token, error) {
-		if newlines > 0 {
-			newlines--
+		if pending > 0 {
+			pending--
 			return End, nil
 		}
 		token, err := t.Next()
 		if err != nil {
 			return nil, err
 		}
-		if len(token.Value()) > 0 && getRuneType([]rune(token.Value())[0]) == runeNewline {
-			newlines = len(token.Value()) - 1
+		if len(token.Value()) > 0 && getRuneType([]rune(token.Value())[0], newlines) == runeNewline {
+			pending = countNewlines(token.Value()) - 1
 			return End, nil
 		}
 		return token, nil
 	})
 }
+
+// countNewlines returns the number of newlines in s, which must only consist
+// of newlines. "\r\n" is counted as a single newline.
+func countNewlines(s string) int {
+	return utf8.RuneCountInString(s) - strings.Count(s, "\r\n")
+}
@@ -65,6 +65,16 @@ var _ = Describe("ReaderTokenizer", func() {
 			"foo\n\nbar\n\n\nfoo bar\n",
 			makeTokens("foo", End, End, "bar", End, End, End, "foo", " ", "bar", End),
 		),
+		Entry(
+			"windows line endings",
+			"foo bar\r\nbaz\r\n\r\n",
+			makeTokens("foo", " ", "bar", End, "baz", End, End),
+		),
+		Entry(
+			"mixed line endings",
+			"a\r\nb\rc\nd\u0085e\u2028f\u2029g\r\r\n\n\u2028h, \r\n",
+			makeTokens("a", End, "b", End, "c", End, "d", End, "e", End, "f", End, "g", End, End, End, End, "h", ", ", End),
+		),
 	)
 	Describe("reading with an io error", func() {
 		It("should return the error", func() {
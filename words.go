@@ -0,0 +1,328 @@
+package gorkov
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordOptions configures the tokenizer created by NewWordTokenizer. The zero
+// value keeps no punctuation inside of words and behaves exactly like
+// NewTokenizer.
+//
+// Apostrophes, Hyphens and NumberSeparators each contain a set of punctuation
+// runes. A rune from one of these sets does not end a literal token if the
+// runes directly before and after it satisfy the condition given for the set.
+type WordOptions struct {
+	// Apostrophes are kept if they are surrounded by letters, as in "don't"
+	// or "rock'n'roll".
+	Apostrophes string
+
+	// Hyphens are kept if they are surrounded by letters or digits, as in
+	// "state-of-the-art" or "COVID-19".
+	Hyphens string
+
+	// NumberSeparators are kept if they are surrounded by digits, as in
+	// "3.14" or "1,000,000".
+	NumberSeparators string
+
+	// SplitIdeographs causes every Han, Hiragana and Katakana character to
+	// be returned as a separate literal token, together with any combining
+	// marks following it. Scripts like these do not separate words with
+	// spaces, so otherwise whole sentences would be returned as one token.
+	SplitIdeographs bool
+
+	// Segmenter, if not nil, is used to split all literal tokens that do not
+	// consist of whitespace and punctuation further. This allows splitting
+	// scripts like Thai or Chinese into words using a dictionary, see
+	// DictionarySegmenter. Segmenter is applied after SplitIdeographs, so
+	// usually only one of them is used.
+	Segmenter Segmenter
+
+	// Sentences causes End tokens to be returned at the end of sentences
+	// instead of at every newline. A sentence ends at whitespace following
+	// terminal punctuation ('.', '!', '?' or '…'), optionally followed by
+	// more punctuation like closing quotes. The whitespace is dropped.
+	// Terminal punctuation ending one of the Abbreviations, like "Dr.",
+	// does not end a sentence. Newlines still end sentences, unless JoinLines
+	// is set. There are never two End tokens in a row and the stream never
+	// starts with an End token.
+	Sentences bool
+
+	// Abbreviations contains words that end in a full stop that does not
+	// end a sentence, such as "e.g.". Case is ignored. If Abbreviations is
+	// nil, DefaultAbbreviations is used.
+	Abbreviations []string
+
+	// JoinLines causes single newlines to be treated like spaces and blank
+	// lines, including lines that only contain whitespace, to be treated as
+	// the end of a paragraph, returning a single End token. The space is
+	// merged into adjacent whitespace and punctuation, and a newline at the
+	// end of the input still returns an End token. This is useful for prose
+	// that is wrapped at a fixed width.
+	JoinLines bool
+
+	// Newlines contains the runes that are considered newlines and are
+	// turned into End tokens. "\r\n" is always treated as a single newline
+	// if '\r' and '\n' are both part of Newlines. If Newlines is empty,
+	// DefaultNewlines is used.
+	Newlines string
+
+	// CollapseSpace causes every run of whitespace in tokens consisting of
+	// whitespace and punctuation to be replaced by a single space. For
+	// example, ",  " and ",\t" both become ", ". Newlines are not affected.
+	CollapseSpace bool
+
+	// SplitPunctuation causes tokens consisting of whitespace and
+	// punctuation to be split into one token per punctuation rune and one
+	// token per run of whitespace. For example, "?! (" becomes "?", "!", " "
+	// and "(".
+	SplitPunctuation bool
+}
+
+// DefaultNewlines contains the runes that are considered newlines by default:
+// line feed, carriage return, next line (U+0085), line separator (U+2028) and
+// paragraph separator (U+2029).
+const DefaultNewlines = "\n\r\u0085\u2028\u2029"
+
+// DefaultWordOptions keeps ASCII and typographic apostrophes and hyphens as
+// well as dots and commas in numbers inside of words.
+var DefaultWordOptions = WordOptions{
+	Apostrophes:      "'’",
+	Hyphens:          "-‐‑",
+	NumberSeparators: ".,",
+}
+
+// NewWordTokenizer creates a new ReaderTokenizer for the given reader that
+// splits its input like NewTokenizer does, except that punctuation inside of
+// words is kept as described by o. For example, with DefaultWordOptions the
+// input "don't panic" results in the literal tokens "don't", " " and "panic".
+func NewWordTokenizer(r io.Reader, o WordOptions) *ReaderTokenizer {
+	s := bufio.NewScanner(r)
+	s.Split(scanWords(o))
+	t := newScannerTokenizer(s)
+	if o.Sentences || o.JoinLines {
+		t = newSentenceTokenizer(t, o)
+	} else {
+		t = newlineToEnd(t, o.newlines())
+	}
+	if o.CollapseSpace || o.SplitPunctuation {
+		t = normalizeSeparators(t, o)
+	}
+	if o.Segmenter != nil {
+		t = segment(t, o.Segmenter, o.newlines())
+	}
+	return &ReaderTokenizer{s: s, t: t}
+}
+
+// newlines returns the runes that are considered newlines.
+func (o WordOptions) newlines() string {
+	if o.Newlines == "" {
+		return DefaultNewlines
+	}
+	return o.Newlines
+}
+
+// joins returns true if r should be kept inside of a word when it is preceded
+// by prev and followed by next.
+func (o WordOptions) joins(prev, r, next rune) bool {
+	switch {
+	case strings.ContainsRune(o.Apostrophes, r):
+		return unicode.IsLetter(prev) && unicode.IsLetter(next)
+	case strings.ContainsRune(o.Hyphens, r):
+		return isAlnum(prev) && isAlnum(next)
+	case strings.ContainsRune(o.NumberSeparators, r):
+		return unicode.IsDigit(prev) && unicode.IsDigit(next)
+	default:
+		return false
+	}
+}
+
+// splits returns true if a literal token starting with first has to end
+// before r.
+func (o WordOptions) splits(first, r rune) bool {
+	if !o.SplitIdeographs {
+		return false
+	}
+	if isIdeograph(first) {
+		return !unicode.In(r, unicode.Mn, unicode.Me)
+	}
+	return isIdeograph(r)
+}
+
+func isIdeograph(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// decodeRune decodes the first rune in data. If data is empty or does not
+// contain a full rune yet and atEOF is false, a width of 0 is returned. An
+// error is returned if data does not start with valid UTF8.
+func decodeRune(data []byte, atEOF bool) (rune, int, error) {
+	if len(data) == 0 {
+		return 0, 0, nil
+	}
+	r, width := utf8.DecodeRune(data)
+	if r == utf8.RuneError && width <= 1 {
+		if !atEOF && !utf8.FullRune(data) {
+			// not enough bytes for a full rune, ask for more
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("invalid UTF8 starting at %X", data)
+	}
+	return r, width, nil
+}
+
+// scanWords returns a bufio.SplitFunc. It returns blocks of contiguous runes
+// that have the same type as defined by getRuneType. Punctuation inside of
+// literal blocks is kept as configured by o.
+func scanWords(o WordOptions) bufio.SplitFunc {
+	return wordScanner{o: o, newlines: o.newlines()}.split
+}
+
+// wordScanner implements the bufio.SplitFunc returned by scanWords.
+type wordScanner struct {
+	o        WordOptions
+	newlines string
+}
+
+func (w wordScanner) split(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	pos, err := w.block(data, atEOF)
+	if err != nil || pos < 0 || (pos == len(data) && !atEOF) {
+		return 0, nil, err
+	}
+	return pos, data[:pos], nil
+}
+
+// block returns the length of the block at the start of data. A negative
+// length means that more data is needed.
+func (w wordScanner) block(data []byte, atEOF bool) (int, error) {
+	r, width, err := decodeRune(data, atEOF)
+	if err != nil || width == 0 {
+		return -1, err
+	}
+	t, pos, first, prev := getRuneType(r, w.newlines), width, r, r
+	for pos < len(data) {
+		n, last, err := w.next(t, first, prev, data[pos:], atEOF)
+		if err != nil || n < 0 {
+			return -1, err
+		}
+		if n == 0 {
+			break
+		}
+		pos, prev = pos+n, last
+	}
+	return pos, nil
+}
+
+// next returns how many bytes at the start of data continue a block of type t
+// that starts with the rune first and ends with the rune prev, together with
+// the last rune of those bytes. A length of 0 means that the block ends before
+// data, a negative length means that more data is needed to decide.
+func (w wordScanner) next(t int, first, prev rune, data []byte, atEOF bool) (int, rune, error) {
+	r, width, err := decodeRune(data, atEOF)
+	switch {
+	case err != nil || width == 0:
+		return -1, 0, err
+	case t == runeLiteral && w.o.splits(first, r):
+		return 0, 0, nil
+	case getRuneType(r, w.newlines) == t:
+		return width, r, nil
+	case t != runeLiteral:
+		return 0, 0, nil
+	}
+	n, last, err := w.joiner(first, prev, r, data[width:], atEOF)
+	if n > 0 {
+		n += width
+	}
+	return n, last, err
+}
+
+// joiner checks whether the punctuation rune r, which follows the literal rune
+// prev, is kept inside of a literal block starting with first. data contains
+// the input following r. It returns the length of the rune following r and
+// that rune if r is kept, with the same conventions as next.
+func (w wordScanner) joiner(first, prev, r rune, data []byte, atEOF bool) (int, rune, error) {
+	next, n, err := decodeRune(data, atEOF)
+	switch {
+	case err != nil:
+		return -1, 0, err
+	case n == 0 && !atEOF:
+		// we need the following rune to decide, ask for more
+		return -1, 0, nil
+	case n == 0 || getRuneType(next, w.newlines) != runeLiteral:
+		return 0, 0, nil
+	case w.o.splits(first, next) || !w.o.joins(prev, r, next):
+		return 0, 0, nil
+	}
+	return n, next, nil
+}
+
+// normalizeSeparators wraps a Tokenizer. The new Tokenizer returns the same
+// stream of tokens as the original, except that literal tokens starting with
+// whitespace or punctuation are modified according to o.CollapseSpace and
+// o.SplitPunctuation.
+func normalizeSeparators(t Tokenizer, o WordOptions) Tokenizer {
+	newlines := o.newlines()
+	var queue []string
+	return TokenizerFunc(func() (Token, error) {
+		for len(queue) == 0 {
+			token, err := t.Next()
+			if err != nil {
+				return nil, err
+			}
+			r, _ := utf8.DecodeRuneInString(token.Value())
+			if token.Type() != LiteralType || getRuneType(r, newlines) != runePunctuation {
+				return token, nil
+			}
+			queue = splitSeparator(token.Value(), o.CollapseSpace, o.SplitPunctuation)
+		}
+		value := queue[0]
+		queue = queue[1:]
+		return Literal(value), nil
+	})
+}
+
+// splitSeparator splits s, which consists of whitespace and punctuation,
+// into single punctuation runes and runs of whitespace if split is true.
+// Runs of whitespace are replaced by a single space if collapse is true.
+func splitSeparator(s string, collapse, split bool) []string {
+	if collapse {
+		s = collapseSpace(s)
+	}
+	if !split {
+		return []string{s}
+	}
+	var result []string
+	start := 0
+	for i, r := range s {
+		prev, _ := utf8.DecodeLastRuneInString(s[:i])
+		if i > 0 && (!unicode.IsSpace(r) || !unicode.IsSpace(prev)) {
+			result = append(result, s[start:i])
+			start = i
+		}
+	}
+	return append(result, s[start:])
+}
+
+// collapseSpace replaces every run of whitespace in s by a single space.
+func collapseSpace(s string) string {
+	result := make([]rune, 0, len(s))
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			result = append(result, r)
+		} else if len(result) == 0 || result[len(result)-1] != ' ' {
+			result = append(result, ' ')
+		}
+	}
+	return string(result)
+}
@@ -0,0 +1,139 @@
+package gorkov_test
+
+import (
+	"io"
+	"strings"
+	"testing/iotest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/Patagonicus/gorkov"
+)
+
+var _ = Describe("WordTokenizer", func() {
+	DescribeTable("reading with the default options",
+		func(input string, expected []Token) {
+			Expect(readAll(NewWordTokenizer(iotest.OneByteReader(strings.NewReader(input)), DefaultWordOptions))).
+				To(Equal(expected))
+		},
+		Entry(
+			"empty input",
+			"",
+			[]Token{},
+		),
+		Entry(
+			"contractions",
+			"don't you’re\n",
+			makeTokens("don't", " ", "you’re", End),
+		),
+		Entry(
+			"hyphenated compounds",
+			"a state-of-the-art COVID-19 test",
+			makeTokens("a", " ", "state-of-the-art", " ", "COVID-19", " ", "test"),
+		),
+		Entry(
+			"numbers",
+			"pi is 3.14, not 1,000.",
+			makeTokens("pi", " ", "is", " ", "3.14", ", ", "not", " ", "1,000", "."),
+		),
+		Entry(
+			"punctuation at the start and end of words",
+			"'quoted' -dash- end.",
+			makeTokens("'", "quoted", "' -", "dash", "- ", "end", "."),
+		),
+		Entry(
+			"repeated joiners",
+			"a--b don''t",
+			makeTokens("a", "--", "b", " ", "don", "''", "t"),
+		),
+		Entry(
+			"joiners of the wrong kind",
+			"a.b 1'2",
+			makeTokens("a", ".", "b", " ", "1", "'", "2"),
+		),
+	)
+	DescribeTable("reading with SplitIdeographs",
+		func(input string, expected []Token) {
+			o := DefaultWordOptions
+			o.SplitIdeographs = true
+			Expect(readAll(NewWordTokenizer(iotest.OneByteReader(strings.NewReader(input)), o))).
+				To(Equal(expected))
+		},
+		Entry(
+			"Chinese",
+			"我爱北京。\n",
+			makeTokens("我", "爱", "北", "京", "。", End),
+		),
+		Entry(
+			"Japanese with combining marks",
+			"カ\u3099タカナとひらがな",
+			makeTokens("カ\u3099", "タ", "カ", "ナ", "と", "ひ", "ら", "が", "な"),
+		),
+		Entry(
+			"mixed scripts",
+			"Go言語's 2.0版",
+			makeTokens("Go", "言", "語", "'", "s", " ", "2.0", "版"),
+		),
+	)
+	DescribeTable("reading with normalized separators",
+		func(input string, collapse, split bool, expected []Token) {
+			o := WordOptions{CollapseSpace: collapse, SplitPunctuation: split}
+			Expect(readAll(NewWordTokenizer(strings.NewReader(input), o))).To(Equal(expected))
+		},
+		Entry(
+			"collapsing whitespace",
+			"a,  b,\tc \t d?! \"e\"\n",
+			true, false,
+			makeTokens("a", ", ", "b", ", ", "c", " ", "d", "?! \"", "e", "\"", End),
+		),
+		Entry(
+			"splitting punctuation",
+			"a,  b?! \"c\"\n",
+			false, true,
+			makeTokens("a", ",", "  ", "b", "?", "!", " ", "\"", "c", "\"", End),
+		),
+		Entry(
+			"collapsing whitespace and splitting punctuation",
+			"a,\t b?! \t(c)",
+			true, true,
+			makeTokens("a", ",", " ", "b", "?", "!", " ", "(", "c", ")"),
+		),
+	)
+	Describe("reading with custom newlines", func() {
+		It("should only turn those runes into End tokens", func() {
+			tokenizer := NewWordTokenizer(strings.NewReader("a\r\nb\u2028c\n"), WordOptions{Newlines: "\n"})
+			Expect(readAll(tokenizer)).To(Equal(makeTokens("a", "\r", End, "b", "\u2028", "c", End)))
+		})
+	})
+	Describe("reading with zero options", func() {
+		It("should behave like NewTokenizer", func() {
+			input := "don't stop-it 3.14\n"
+			Expect(readAll(NewWordTokenizer(strings.NewReader(input), WordOptions{}))).
+				To(Equal(readAll(NewTokenizer(strings.NewReader(input)))))
+		})
+	})
+	Describe("reading invalid UTF8 after a joiner", func() {
+		It("should return an error", func() {
+			tokenizer := NewWordTokenizer(strings.NewReader("don'"+"☹"[:1]), DefaultWordOptions)
+			_, err := tokenizer.Next()
+			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(Equal(io.EOF))
+		})
+	})
+})
+
+// readAll reads tokens from t until io.EOF is returned. Any other error
+// fails the current spec.
+func readAll(t Tokenizer) []Token {
+	result := []Token{}
+	for {
+		token, err := t.Next()
+		if err == io.EOF {
+			return result
+		}
+		Expect(err).NotTo(HaveOccurred())
+		result = append(result, token)
+	}
+}